@@ -21,32 +21,988 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// How a listing's total_count is computed
+type TotalCountMode int32
+
+const (
+	// No total count is returned
+	TotalCountMode_TOTAL_COUNT_MODE_UNSPECIFIED TotalCountMode = 0
+	// Count every matching user
+	TotalCountMode_TOTAL_COUNT_MODE_EXACT TotalCountMode = 1
+	// Use the database's estimate, which avoids a full scan on large tables
+	TotalCountMode_TOTAL_COUNT_MODE_ESTIMATED TotalCountMode = 2
+)
+
+// Enum value maps for TotalCountMode.
+var (
+	TotalCountMode_name = map[int32]string{
+		0: "TOTAL_COUNT_MODE_UNSPECIFIED",
+		1: "TOTAL_COUNT_MODE_EXACT",
+		2: "TOTAL_COUNT_MODE_ESTIMATED",
+	}
+	TotalCountMode_value = map[string]int32{
+		"TOTAL_COUNT_MODE_UNSPECIFIED": 0,
+		"TOTAL_COUNT_MODE_EXACT":       1,
+		"TOTAL_COUNT_MODE_ESTIMATED":   2,
+	}
+)
+
+func (x TotalCountMode) Enum() *TotalCountMode {
+	p := new(TotalCountMode)
+	*p = x
+	return p
+}
+
+func (x TotalCountMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TotalCountMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_grpc_user_proto_enumTypes[0].Descriptor()
+}
+
+func (TotalCountMode) Type() protoreflect.EnumType {
+	return &file_api_grpc_user_proto_enumTypes[0]
+}
+
+func (x TotalCountMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TotalCountMode.Descriptor instead.
+func (TotalCountMode) EnumDescriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{0}
+}
+
+// Outcome of one user in a bulk create
+type BulkCreateStatus int32
+
+const (
+	BulkCreateStatus_BULK_CREATE_STATUS_UNSPECIFIED BulkCreateStatus = 0
+	BulkCreateStatus_BULK_CREATE_STATUS_CREATED     BulkCreateStatus = 1
+	// The email was already taken, possibly by an earlier user in the stream
+	BulkCreateStatus_BULK_CREATE_STATUS_DUPLICATE BulkCreateStatus = 2
+	BulkCreateStatus_BULK_CREATE_STATUS_INVALID   BulkCreateStatus = 3
+)
+
+// Enum value maps for BulkCreateStatus.
+var (
+	BulkCreateStatus_name = map[int32]string{
+		0: "BULK_CREATE_STATUS_UNSPECIFIED",
+		1: "BULK_CREATE_STATUS_CREATED",
+		2: "BULK_CREATE_STATUS_DUPLICATE",
+		3: "BULK_CREATE_STATUS_INVALID",
+	}
+	BulkCreateStatus_value = map[string]int32{
+		"BULK_CREATE_STATUS_UNSPECIFIED": 0,
+		"BULK_CREATE_STATUS_CREATED":     1,
+		"BULK_CREATE_STATUS_DUPLICATE":   2,
+		"BULK_CREATE_STATUS_INVALID":     3,
+	}
+)
+
+func (x BulkCreateStatus) Enum() *BulkCreateStatus {
+	p := new(BulkCreateStatus)
+	*p = x
+	return p
+}
+
+func (x BulkCreateStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BulkCreateStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_grpc_user_proto_enumTypes[1].Descriptor()
+}
+
+func (BulkCreateStatus) Type() protoreflect.EnumType {
+	return &file_api_grpc_user_proto_enumTypes[1]
+}
+
+func (x BulkCreateStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BulkCreateStatus.Descriptor instead.
+func (BulkCreateStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{1}
+}
+
+// Kind of change a UserEvent reports
+type UserEventType int32
+
+const (
+	UserEventType_USER_EVENT_TYPE_UNSPECIFIED UserEventType = 0
+	UserEventType_USER_EVENT_TYPE_CREATED     UserEventType = 1
+	UserEventType_USER_EVENT_TYPE_UPDATED     UserEventType = 2
+	// The user was soft-deleted
+	UserEventType_USER_EVENT_TYPE_DELETED  UserEventType = 3
+	UserEventType_USER_EVENT_TYPE_RESTORED UserEventType = 4
+	// The user was permanently removed
+	UserEventType_USER_EVENT_TYPE_PURGED UserEventType = 5
+)
+
+// Enum value maps for UserEventType.
+var (
+	UserEventType_name = map[int32]string{
+		0: "USER_EVENT_TYPE_UNSPECIFIED",
+		1: "USER_EVENT_TYPE_CREATED",
+		2: "USER_EVENT_TYPE_UPDATED",
+		3: "USER_EVENT_TYPE_DELETED",
+		4: "USER_EVENT_TYPE_RESTORED",
+		5: "USER_EVENT_TYPE_PURGED",
+	}
+	UserEventType_value = map[string]int32{
+		"USER_EVENT_TYPE_UNSPECIFIED": 0,
+		"USER_EVENT_TYPE_CREATED":     1,
+		"USER_EVENT_TYPE_UPDATED":     2,
+		"USER_EVENT_TYPE_DELETED":     3,
+		"USER_EVENT_TYPE_RESTORED":    4,
+		"USER_EVENT_TYPE_PURGED":      5,
+	}
+)
+
+func (x UserEventType) Enum() *UserEventType {
+	p := new(UserEventType)
+	*p = x
+	return p
+}
+
+func (x UserEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UserEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_grpc_user_proto_enumTypes[2].Descriptor()
+}
+
+func (UserEventType) Type() protoreflect.EnumType {
+	return &file_api_grpc_user_proto_enumTypes[2]
+}
+
+func (x UserEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UserEventType.Descriptor instead.
+func (UserEventType) EnumDescriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{2}
+}
+
 type User struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email     string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Name      string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt string                 `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Version   int64                  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	// Empty unless the user is soft-deleted
+	DeletedAt     string `protobuf:"bytes,7,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_api_grpc_user_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *User) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *User) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *User) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *User) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *User) GetDeletedAt() string {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return ""
+}
+
+type CreateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_api_grpc_user_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type BulkCreateResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the user in the request stream
+	Index  int32            `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Status BulkCreateStatus `protobuf:"varint,2,opt,name=status,proto3,enum=user.BulkCreateStatus" json:"status,omitempty"`
+	// Set when the user was created
+	User *User `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	// Why the user was not created
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkCreateResult) Reset() {
+	*x = BulkCreateResult{}
+	mi := &file_api_grpc_user_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCreateResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCreateResult) ProtoMessage() {}
+
+func (x *BulkCreateResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCreateResult.ProtoReflect.Descriptor instead.
+func (*BulkCreateResult) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BulkCreateResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BulkCreateResult) GetStatus() BulkCreateStatus {
+	if x != nil {
+		return x.Status
+	}
+	return BulkCreateStatus_BULK_CREATE_STATUS_UNSPECIFIED
+}
+
+func (x *BulkCreateResult) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *BulkCreateResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BulkCreateUsersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One result per streamed user, in order
+	Results       []*BulkCreateResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkCreateUsersResponse) Reset() {
+	*x = BulkCreateUsersResponse{}
+	mi := &file_api_grpc_user_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCreateUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCreateUsersResponse) ProtoMessage() {}
+
+func (x *BulkCreateUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCreateUsersResponse.ProtoReflect.Descriptor instead.
+func (*BulkCreateUsersResponse) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BulkCreateUsersResponse) GetResults() []*BulkCreateResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetUserRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Also returns a soft-deleted user. There is no authorization layer, so
+	// any caller may set it.
+	IncludeDeleted bool `protobuf:"varint,2,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_api_grpc_user_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetUserRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type ListUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Limit int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Deprecated: use page_token, which stays consistent while users are
+	// created. Offset paging cannot be combined with page_token.
+	Offset int32 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Also returns soft-deleted users. There is no authorization layer, so
+	// any caller may set it.
+	IncludeDeleted bool `protobuf:"varint,3,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	// next_page_token from the previous response; empty for the first page.
+	// The filters and order_by must not change between pages.
+	PageToken string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Matches emails starting with email_prefix, ignoring case
+	EmailPrefix string `protobuf:"bytes,5,opt,name=email_prefix,json=emailPrefix,proto3" json:"email_prefix,omitempty"`
+	// Matches names containing name_contains, ignoring case
+	NameContains string `protobuf:"bytes,6,opt,name=name_contains,json=nameContains,proto3" json:"name_contains,omitempty"`
+	// RFC 3339 bounds on created_at and updated_at; the after bounds are
+	// inclusive and the before bounds exclusive
+	CreatedAfter  string `protobuf:"bytes,7,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore string `protobuf:"bytes,8,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	UpdatedAfter  string `protobuf:"bytes,9,opt,name=updated_after,json=updatedAfter,proto3" json:"updated_after,omitempty"`
+	UpdatedBefore string `protobuf:"bytes,10,opt,name=updated_before,json=updatedBefore,proto3" json:"updated_before,omitempty"`
+	// One of "created_at", "name" or "email", optionally followed by "desc".
+	// Defaults to "created_at desc".
+	OrderBy        string         `protobuf:"bytes,11,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	TotalCountMode TotalCountMode `protobuf:"varint,12,opt,name=total_count_mode,json=totalCountMode,proto3,enum=user.TotalCountMode" json:"total_count_mode,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_api_grpc_user_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListUsersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+func (x *ListUsersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetEmailPrefix() string {
+	if x != nil {
+		return x.EmailPrefix
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetNameContains() string {
+	if x != nil {
+		return x.NameContains
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetCreatedAfter() string {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetCreatedBefore() string {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetUpdatedAfter() string {
+	if x != nil {
+		return x.UpdatedAfter
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetUpdatedBefore() string {
+	if x != nil {
+		return x.UpdatedBefore
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetTotalCountMode() TotalCountMode {
+	if x != nil {
+		return x.TotalCountMode
+	}
+	return TotalCountMode_TOTAL_COUNT_MODE_UNSPECIFIED
+}
+
+type ListUsersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Users []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	// Empty on the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Number of users matching the filters across all pages; only set when
+	// total_count_mode asks for it
+	TotalCount    *int64 `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3,oneof" json:"total_count,omitempty"`
+	HasNextPage   bool   `protobuf:"varint,4,opt,name=has_next_page,json=hasNextPage,proto3" json:"has_next_page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_api_grpc_user_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListUsersResponse) GetTotalCount() int64 {
+	if x != nil && x.TotalCount != nil {
+		return *x.TotalCount
+	}
+	return 0
+}
+
+func (x *ListUsersResponse) GetHasNextPage() bool {
+	if x != nil {
+		return x.HasNextPage
+	}
+	return false
+}
+
+// Takes the same filters as ListUsersRequest
+type CountUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Also returns soft-deleted users. There is no authorization layer, so
+	// any caller may set it.
+	IncludeDeleted bool   `protobuf:"varint,1,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	EmailPrefix    string `protobuf:"bytes,2,opt,name=email_prefix,json=emailPrefix,proto3" json:"email_prefix,omitempty"`
+	NameContains   string `protobuf:"bytes,3,opt,name=name_contains,json=nameContains,proto3" json:"name_contains,omitempty"`
+	CreatedAfter   string `protobuf:"bytes,4,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore  string `protobuf:"bytes,5,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	UpdatedAfter   string `protobuf:"bytes,6,opt,name=updated_after,json=updatedAfter,proto3" json:"updated_after,omitempty"`
+	UpdatedBefore  string `protobuf:"bytes,7,opt,name=updated_before,json=updatedBefore,proto3" json:"updated_before,omitempty"`
+	// Return the database's estimate instead of an exact count
+	Estimate      bool `protobuf:"varint,8,opt,name=estimate,proto3" json:"estimate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountUsersRequest) Reset() {
+	*x = CountUsersRequest{}
+	mi := &file_api_grpc_user_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountUsersRequest) ProtoMessage() {}
+
+func (x *CountUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountUsersRequest.ProtoReflect.Descriptor instead.
+func (*CountUsersRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CountUsersRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+func (x *CountUsersRequest) GetEmailPrefix() string {
+	if x != nil {
+		return x.EmailPrefix
+	}
+	return ""
+}
+
+func (x *CountUsersRequest) GetNameContains() string {
+	if x != nil {
+		return x.NameContains
+	}
+	return ""
+}
+
+func (x *CountUsersRequest) GetCreatedAfter() string {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return ""
+}
+
+func (x *CountUsersRequest) GetCreatedBefore() string {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return ""
+}
+
+func (x *CountUsersRequest) GetUpdatedAfter() string {
+	if x != nil {
+		return x.UpdatedAfter
+	}
+	return ""
+}
+
+func (x *CountUsersRequest) GetUpdatedBefore() string {
+	if x != nil {
+		return x.UpdatedBefore
+	}
+	return ""
+}
+
+func (x *CountUsersRequest) GetEstimate() bool {
+	if x != nil {
+		return x.Estimate
+	}
+	return false
+}
+
+type CountUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountUsersResponse) Reset() {
+	*x = CountUsersResponse{}
+	mi := &file_api_grpc_user_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountUsersResponse) ProtoMessage() {}
+
+func (x *CountUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountUsersResponse.ProtoReflect.Descriptor instead.
+func (*CountUsersResponse) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CountUsersResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type UpdateUserRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email *string                `protobuf:"bytes,2,opt,name=email,proto3,oneof" json:"email,omitempty"`
+	Name  *string                `protobuf:"bytes,3,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	// When set, the update fails with ABORTED unless it matches the user's
+	// current version
+	ExpectedVersion *int64 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
+	mi := &file_api_grpc_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserRequest) ProtoMessage() {}
+
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetEmail() string {
+	if x != nil && x.Email != nil {
+		return *x.Email
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+type DeleteUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserRequest) Reset() {
+	*x = DeleteUserRequest{}
+	mi := &file_api_grpc_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserRequest) ProtoMessage() {}
+
+func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteUserResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     string                 `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *User) Reset() {
-	*x = User{}
-	mi := &file_api_grpc_user_proto_msgTypes[0]
+func (x *DeleteUserResponse) Reset() {
+	*x = DeleteUserResponse{}
+	mi := &file_api_grpc_user_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *User) String() string {
+func (x *DeleteUserResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*User) ProtoMessage() {}
+func (*DeleteUserResponse) ProtoMessage() {}
 
-func (x *User) ProtoReflect() protoreflect.Message {
-	mi := &file_api_grpc_user_proto_msgTypes[0]
+func (x *DeleteUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -57,69 +1013,84 @@ func (x *User) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use User.ProtoReflect.Descriptor instead.
-func (*User) Descriptor() ([]byte, []int) {
-	return file_api_grpc_user_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use DeleteUserResponse.ProtoReflect.Descriptor instead.
+func (*DeleteUserResponse) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *User) GetId() string {
+func (x *DeleteUserResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Id
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-func (x *User) GetEmail() string {
-	if x != nil {
-		return x.Email
-	}
-	return ""
+type RestoreUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *User) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
+func (x *RestoreUserRequest) Reset() {
+	*x = RestoreUserRequest{}
+	mi := &file_api_grpc_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *User) GetCreatedAt() string {
+func (x *RestoreUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreUserRequest) ProtoMessage() {}
+
+func (x *RestoreUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[12]
 	if x != nil {
-		return x.CreatedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *User) GetUpdatedAt() string {
+// Deprecated: Use RestoreUserRequest.ProtoReflect.Descriptor instead.
+func (*RestoreUserRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RestoreUserRequest) GetId() string {
 	if x != nil {
-		return x.UpdatedAt
+		return x.Id
 	}
 	return ""
 }
 
-type CreateUserRequest struct {
+type PurgeUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateUserRequest) Reset() {
-	*x = CreateUserRequest{}
-	mi := &file_api_grpc_user_proto_msgTypes[1]
+func (x *PurgeUserRequest) Reset() {
+	*x = PurgeUserRequest{}
+	mi := &file_api_grpc_user_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateUserRequest) String() string {
+func (x *PurgeUserRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateUserRequest) ProtoMessage() {}
+func (*PurgeUserRequest) ProtoMessage() {}
 
-func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_grpc_user_proto_msgTypes[1]
+func (x *PurgeUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -130,47 +1101,40 @@ func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
-func (*CreateUserRequest) Descriptor() ([]byte, []int) {
-	return file_api_grpc_user_proto_rawDescGZIP(), []int{1}
-}
-
-func (x *CreateUserRequest) GetEmail() string {
-	if x != nil {
-		return x.Email
-	}
-	return ""
+// Deprecated: Use PurgeUserRequest.ProtoReflect.Descriptor instead.
+func (*PurgeUserRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *CreateUserRequest) GetName() string {
+func (x *PurgeUserRequest) GetId() string {
 	if x != nil {
-		return x.Name
+		return x.Id
 	}
 	return ""
 }
 
-type GetUserRequest struct {
+type PurgeUserResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserRequest) Reset() {
-	*x = GetUserRequest{}
-	mi := &file_api_grpc_user_proto_msgTypes[2]
+func (x *PurgeUserResponse) Reset() {
+	*x = PurgeUserResponse{}
+	mi := &file_api_grpc_user_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserRequest) String() string {
+func (x *PurgeUserResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserRequest) ProtoMessage() {}
+func (*PurgeUserResponse) ProtoMessage() {}
 
-func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_grpc_user_proto_msgTypes[2]
+func (x *PurgeUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -181,41 +1145,43 @@ func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
-func (*GetUserRequest) Descriptor() ([]byte, []int) {
-	return file_api_grpc_user_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use PurgeUserResponse.ProtoReflect.Descriptor instead.
+func (*PurgeUserResponse) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *GetUserRequest) GetId() string {
+func (x *PurgeUserResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Id
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-type ListUsersRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+type SearchUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Free text matched against names and emails, tolerating typos
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Defaults to 10; at most 100 results are returned
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUsersRequest) Reset() {
-	*x = ListUsersRequest{}
-	mi := &file_api_grpc_user_proto_msgTypes[3]
+func (x *SearchUsersRequest) Reset() {
+	*x = SearchUsersRequest{}
+	mi := &file_api_grpc_user_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUsersRequest) String() string {
+func (x *SearchUsersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUsersRequest) ProtoMessage() {}
+func (*SearchUsersRequest) ProtoMessage() {}
 
-func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_grpc_user_proto_msgTypes[3]
+func (x *SearchUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -226,47 +1192,49 @@ func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
-func (*ListUsersRequest) Descriptor() ([]byte, []int) {
-	return file_api_grpc_user_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use SearchUsersRequest.ProtoReflect.Descriptor instead.
+func (*SearchUsersRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *ListUsersRequest) GetLimit() int32 {
+func (x *SearchUsersRequest) GetQuery() string {
 	if x != nil {
-		return x.Limit
+		return x.Query
 	}
-	return 0
+	return ""
 }
 
-func (x *ListUsersRequest) GetOffset() int32 {
+func (x *SearchUsersRequest) GetLimit() int32 {
 	if x != nil {
-		return x.Offset
+		return x.Limit
 	}
 	return 0
 }
 
-type ListUsersResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+type UserSearchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	User  *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// Relevance of the match; only comparable within one response
+	Score         float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUsersResponse) Reset() {
-	*x = ListUsersResponse{}
-	mi := &file_api_grpc_user_proto_msgTypes[4]
+func (x *UserSearchResult) Reset() {
+	*x = UserSearchResult{}
+	mi := &file_api_grpc_user_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUsersResponse) String() string {
+func (x *UserSearchResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUsersResponse) ProtoMessage() {}
+func (*UserSearchResult) ProtoMessage() {}
 
-func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_grpc_user_proto_msgTypes[4]
+func (x *UserSearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -277,42 +1245,48 @@ func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
-func (*ListUsersResponse) Descriptor() ([]byte, []int) {
-	return file_api_grpc_user_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use UserSearchResult.ProtoReflect.Descriptor instead.
+func (*UserSearchResult) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *ListUsersResponse) GetUsers() []*User {
+func (x *UserSearchResult) GetUser() *User {
 	if x != nil {
-		return x.Users
+		return x.User
 	}
 	return nil
 }
 
-type UpdateUserRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Email         *string                `protobuf:"bytes,2,opt,name=email,proto3,oneof" json:"email,omitempty"`
-	Name          *string                `protobuf:"bytes,3,opt,name=name,proto3,oneof" json:"name,omitempty"`
+func (x *UserSearchResult) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type SearchUsersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Best match first
+	Results       []*UserSearchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateUserRequest) Reset() {
-	*x = UpdateUserRequest{}
-	mi := &file_api_grpc_user_proto_msgTypes[5]
+func (x *SearchUsersResponse) Reset() {
+	*x = SearchUsersResponse{}
+	mi := &file_api_grpc_user_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateUserRequest) String() string {
+func (x *SearchUsersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateUserRequest) ProtoMessage() {}
+func (*SearchUsersResponse) ProtoMessage() {}
 
-func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_grpc_user_proto_msgTypes[5]
+func (x *SearchUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -323,54 +1297,40 @@ func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
-func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
-	return file_api_grpc_user_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use SearchUsersResponse.ProtoReflect.Descriptor instead.
+func (*SearchUsersResponse) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *UpdateUserRequest) GetId() string {
+func (x *SearchUsersResponse) GetResults() []*UserSearchResult {
 	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-func (x *UpdateUserRequest) GetEmail() string {
-	if x != nil && x.Email != nil {
-		return *x.Email
-	}
-	return ""
-}
-
-func (x *UpdateUserRequest) GetName() string {
-	if x != nil && x.Name != nil {
-		return *x.Name
+		return x.Results
 	}
-	return ""
+	return nil
 }
 
-type DeleteUserRequest struct {
+type UserResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteUserRequest) Reset() {
-	*x = DeleteUserRequest{}
-	mi := &file_api_grpc_user_proto_msgTypes[6]
+func (x *UserResponse) Reset() {
+	*x = UserResponse{}
+	mi := &file_api_grpc_user_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteUserRequest) String() string {
+func (x *UserResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteUserRequest) ProtoMessage() {}
+func (*UserResponse) ProtoMessage() {}
 
-func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_grpc_user_proto_msgTypes[6]
+func (x *UserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -381,40 +1341,43 @@ func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
-func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
-	return file_api_grpc_user_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use UserResponse.ProtoReflect.Descriptor instead.
+func (*UserResponse) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *DeleteUserRequest) GetId() string {
+func (x *UserResponse) GetUser() *User {
 	if x != nil {
-		return x.Id
+		return x.User
 	}
-	return ""
+	return nil
 }
 
-type DeleteUserResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+type WatchUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the last event received, to resume after it; empty to receive the
+	// events from now on. Fails with INVALID_ARGUMENT if the events after it
+	// are no longer retained.
+	After         string `protobuf:"bytes,1,opt,name=after,proto3" json:"after,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteUserResponse) Reset() {
-	*x = DeleteUserResponse{}
-	mi := &file_api_grpc_user_proto_msgTypes[7]
+func (x *WatchUsersRequest) Reset() {
+	*x = WatchUsersRequest{}
+	mi := &file_api_grpc_user_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteUserResponse) String() string {
+func (x *WatchUsersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteUserResponse) ProtoMessage() {}
+func (*WatchUsersRequest) ProtoMessage() {}
 
-func (x *DeleteUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_grpc_user_proto_msgTypes[7]
+func (x *WatchUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -425,40 +1388,45 @@ func (x *DeleteUserResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteUserResponse.ProtoReflect.Descriptor instead.
-func (*DeleteUserResponse) Descriptor() ([]byte, []int) {
-	return file_api_grpc_user_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use WatchUsersRequest.ProtoReflect.Descriptor instead.
+func (*WatchUsersRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *DeleteUserResponse) GetSuccess() bool {
+func (x *WatchUsersRequest) GetAfter() string {
 	if x != nil {
-		return x.Success
+		return x.After
 	}
-	return false
+	return ""
 }
 
-type UserResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+type UserEvent struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type   UserEventType          `protobuf:"varint,2,opt,name=type,proto3,enum=user.UserEventType" json:"type,omitempty"`
+	UserId string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// The user after the change; unset for deletes and purges
+	User          *User  `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	OccurredAt    string `protobuf:"bytes,5,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserResponse) Reset() {
-	*x = UserResponse{}
-	mi := &file_api_grpc_user_proto_msgTypes[8]
+func (x *UserEvent) Reset() {
+	*x = UserEvent{}
+	mi := &file_api_grpc_user_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserResponse) String() string {
+func (x *UserEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserResponse) ProtoMessage() {}
+func (*UserEvent) ProtoMessage() {}
 
-func (x *UserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_grpc_user_proto_msgTypes[8]
+func (x *UserEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_user_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -469,23 +1437,51 @@ func (x *UserResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserResponse.ProtoReflect.Descriptor instead.
-func (*UserResponse) Descriptor() ([]byte, []int) {
-	return file_api_grpc_user_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use UserEvent.ProtoReflect.Descriptor instead.
+func (*UserEvent) Descriptor() ([]byte, []int) {
+	return file_api_grpc_user_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *UserResponse) GetUser() *User {
+func (x *UserEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UserEvent) GetType() UserEventType {
+	if x != nil {
+		return x.Type
+	}
+	return UserEventType_USER_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *UserEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserEvent) GetUser() *User {
 	if x != nil {
 		return x.User
 	}
 	return nil
 }
 
+func (x *UserEvent) GetOccurredAt() string {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return ""
+}
+
 var File_api_grpc_user_proto protoreflect.FileDescriptor
 
 const file_api_grpc_user_proto_rawDesc = "" +
 	"\n" +
-	"\x13api/grpc/user.proto\x12\x04user\"~\n" +
+	"\x13api/grpc/user.proto\x12\x04user\"\xb7\x01\n" +
 	"\x04User\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
@@ -493,40 +1489,131 @@ const file_api_grpc_user_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\x05 \x01(\tR\tupdatedAt\"=\n" +
+	"updated_at\x18\x05 \x01(\tR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x03R\aversion\x12\x1d\n" +
+	"\n" +
+	"deleted_at\x18\a \x01(\tR\tdeletedAt\"=\n" +
 	"\x11CreateUserRequest\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\" \n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\x8e\x01\n" +
+	"\x10BulkCreateResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12.\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x16.user.BulkCreateStatusR\x06status\x12\x1e\n" +
+	"\x04user\x18\x03 \x01(\v2\n" +
+	".user.UserR\x04user\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"K\n" +
+	"\x17BulkCreateUsersResponse\x120\n" +
+	"\aresults\x18\x01 \x03(\v2\x16.user.BulkCreateResultR\aresults\"I\n" +
 	"\x0eGetUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"@\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0finclude_deleted\x18\x02 \x01(\bR\x0eincludeDeleted\"\xc3\x03\n" +
 	"\x10ListUsersRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\"5\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12'\n" +
+	"\x0finclude_deleted\x18\x03 \x01(\bR\x0eincludeDeleted\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12!\n" +
+	"\femail_prefix\x18\x05 \x01(\tR\vemailPrefix\x12#\n" +
+	"\rname_contains\x18\x06 \x01(\tR\fnameContains\x12#\n" +
+	"\rcreated_after\x18\a \x01(\tR\fcreatedAfter\x12%\n" +
+	"\x0ecreated_before\x18\b \x01(\tR\rcreatedBefore\x12#\n" +
+	"\rupdated_after\x18\t \x01(\tR\fupdatedAfter\x12%\n" +
+	"\x0eupdated_before\x18\n" +
+	" \x01(\tR\rupdatedBefore\x12\x19\n" +
+	"\border_by\x18\v \x01(\tR\aorderBy\x12>\n" +
+	"\x10total_count_mode\x18\f \x01(\x0e2\x14.user.TotalCountModeR\x0etotalCountMode\"\xb7\x01\n" +
 	"\x11ListUsersResponse\x12 \n" +
 	"\x05users\x18\x01 \x03(\v2\n" +
-	".user.UserR\x05users\"j\n" +
+	".user.UserR\x05users\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12$\n" +
+	"\vtotal_count\x18\x03 \x01(\x03H\x00R\n" +
+	"totalCount\x88\x01\x01\x12\"\n" +
+	"\rhas_next_page\x18\x04 \x01(\bR\vhasNextPageB\x0e\n" +
+	"\f_total_count\"\xb8\x02\n" +
+	"\x11CountUsersRequest\x12'\n" +
+	"\x0finclude_deleted\x18\x01 \x01(\bR\x0eincludeDeleted\x12!\n" +
+	"\femail_prefix\x18\x02 \x01(\tR\vemailPrefix\x12#\n" +
+	"\rname_contains\x18\x03 \x01(\tR\fnameContains\x12#\n" +
+	"\rcreated_after\x18\x04 \x01(\tR\fcreatedAfter\x12%\n" +
+	"\x0ecreated_before\x18\x05 \x01(\tR\rcreatedBefore\x12#\n" +
+	"\rupdated_after\x18\x06 \x01(\tR\fupdatedAfter\x12%\n" +
+	"\x0eupdated_before\x18\a \x01(\tR\rupdatedBefore\x12\x1a\n" +
+	"\bestimate\x18\b \x01(\bR\bestimate\"*\n" +
+	"\x12CountUsersResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\"\xaf\x01\n" +
 	"\x11UpdateUserRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
 	"\x05email\x18\x02 \x01(\tH\x00R\x05email\x88\x01\x01\x12\x17\n" +
-	"\x04name\x18\x03 \x01(\tH\x01R\x04name\x88\x01\x01B\b\n" +
+	"\x04name\x18\x03 \x01(\tH\x01R\x04name\x88\x01\x01\x12.\n" +
+	"\x10expected_version\x18\x04 \x01(\x03H\x02R\x0fexpectedVersion\x88\x01\x01B\b\n" +
 	"\x06_emailB\a\n" +
-	"\x05_name\"#\n" +
+	"\x05_nameB\x13\n" +
+	"\x11_expected_version\"#\n" +
 	"\x11DeleteUserRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\".\n" +
 	"\x12DeleteUserResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\".\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"$\n" +
+	"\x12RestoreUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\"\n" +
+	"\x10PurgeUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"-\n" +
+	"\x11PurgeUserResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"@\n" +
+	"\x12SearchUsersRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"H\n" +
+	"\x10UserSearchResult\x12\x1e\n" +
+	"\x04user\x18\x01 \x01(\v2\n" +
+	".user.UserR\x04user\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\"G\n" +
+	"\x13SearchUsersResponse\x120\n" +
+	"\aresults\x18\x01 \x03(\v2\x16.user.UserSearchResultR\aresults\".\n" +
 	"\fUserResponse\x12\x1e\n" +
 	"\x04user\x18\x01 \x01(\v2\n" +
-	".user.UserR\x04user2\xb7\x02\n" +
+	".user.UserR\x04user\")\n" +
+	"\x11WatchUsersRequest\x12\x14\n" +
+	"\x05after\x18\x01 \x01(\tR\x05after\"\x9e\x01\n" +
+	"\tUserEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x13.user.UserEventTypeR\x04type\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1e\n" +
+	"\x04user\x18\x04 \x01(\v2\n" +
+	".user.UserR\x04user\x12\x1f\n" +
+	"\voccurred_at\x18\x05 \x01(\tR\n" +
+	"occurredAt*n\n" +
+	"\x0eTotalCountMode\x12 \n" +
+	"\x1cTOTAL_COUNT_MODE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16TOTAL_COUNT_MODE_EXACT\x10\x01\x12\x1e\n" +
+	"\x1aTOTAL_COUNT_MODE_ESTIMATED\x10\x02*\x98\x01\n" +
+	"\x10BulkCreateStatus\x12\"\n" +
+	"\x1eBULK_CREATE_STATUS_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aBULK_CREATE_STATUS_CREATED\x10\x01\x12 \n" +
+	"\x1cBULK_CREATE_STATUS_DUPLICATE\x10\x02\x12\x1e\n" +
+	"\x1aBULK_CREATE_STATUS_INVALID\x10\x03*\xc1\x01\n" +
+	"\rUserEventType\x12\x1f\n" +
+	"\x1bUSER_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17USER_EVENT_TYPE_CREATED\x10\x01\x12\x1b\n" +
+	"\x17USER_EVENT_TYPE_UPDATED\x10\x02\x12\x1b\n" +
+	"\x17USER_EVENT_TYPE_DELETED\x10\x03\x12\x1c\n" +
+	"\x18USER_EVENT_TYPE_RESTORED\x10\x04\x12\x1a\n" +
+	"\x16USER_EVENT_TYPE_PURGED\x10\x052\xbe\x05\n" +
 	"\vUserService\x129\n" +
 	"\n" +
-	"CreateUser\x12\x17.user.CreateUserRequest\x1a\x12.user.UserResponse\x123\n" +
+	"CreateUser\x12\x17.user.CreateUserRequest\x1a\x12.user.UserResponse\x12K\n" +
+	"\x0fBulkCreateUsers\x12\x17.user.CreateUserRequest\x1a\x1d.user.BulkCreateUsersResponse(\x01\x123\n" +
 	"\aGetUser\x12\x14.user.GetUserRequest\x1a\x12.user.UserResponse\x12<\n" +
 	"\tListUsers\x12\x16.user.ListUsersRequest\x1a\x17.user.ListUsersResponse\x129\n" +
 	"\n" +
 	"UpdateUser\x12\x17.user.UpdateUserRequest\x1a\x12.user.UserResponse\x12?\n" +
 	"\n" +
-	"DeleteUser\x12\x17.user.DeleteUserRequest\x1a\x18.user.DeleteUserResponseBDZBgithub.com/alexanderbkl/golang-hexagonal-boilerplate/api/grpc/userb\x06proto3"
+	"DeleteUser\x12\x17.user.DeleteUserRequest\x1a\x18.user.DeleteUserResponse\x12;\n" +
+	"\vRestoreUser\x12\x18.user.RestoreUserRequest\x1a\x12.user.UserResponse\x12<\n" +
+	"\tPurgeUser\x12\x16.user.PurgeUserRequest\x1a\x17.user.PurgeUserResponse\x12B\n" +
+	"\vSearchUsers\x12\x18.user.SearchUsersRequest\x1a\x19.user.SearchUsersResponse\x12?\n" +
+	"\n" +
+	"CountUsers\x12\x17.user.CountUsersRequest\x1a\x18.user.CountUsersResponse\x128\n" +
+	"\n" +
+	"WatchUsers\x12\x17.user.WatchUsersRequest\x1a\x0f.user.UserEvent0\x01BDZBgithub.com/alexanderbkl/golang-hexagonal-boilerplate/api/grpc/userb\x06proto3"
 
 var (
 	file_api_grpc_user_proto_rawDescOnce sync.Once
@@ -540,36 +1627,72 @@ func file_api_grpc_user_proto_rawDescGZIP() []byte {
 	return file_api_grpc_user_proto_rawDescData
 }
 
-var file_api_grpc_user_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_api_grpc_user_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_api_grpc_user_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_api_grpc_user_proto_goTypes = []any{
-	(*User)(nil),               // 0: user.User
-	(*CreateUserRequest)(nil),  // 1: user.CreateUserRequest
-	(*GetUserRequest)(nil),     // 2: user.GetUserRequest
-	(*ListUsersRequest)(nil),   // 3: user.ListUsersRequest
-	(*ListUsersResponse)(nil),  // 4: user.ListUsersResponse
-	(*UpdateUserRequest)(nil),  // 5: user.UpdateUserRequest
-	(*DeleteUserRequest)(nil),  // 6: user.DeleteUserRequest
-	(*DeleteUserResponse)(nil), // 7: user.DeleteUserResponse
-	(*UserResponse)(nil),       // 8: user.UserResponse
+	(TotalCountMode)(0),             // 0: user.TotalCountMode
+	(BulkCreateStatus)(0),           // 1: user.BulkCreateStatus
+	(UserEventType)(0),              // 2: user.UserEventType
+	(*User)(nil),                    // 3: user.User
+	(*CreateUserRequest)(nil),       // 4: user.CreateUserRequest
+	(*BulkCreateResult)(nil),        // 5: user.BulkCreateResult
+	(*BulkCreateUsersResponse)(nil), // 6: user.BulkCreateUsersResponse
+	(*GetUserRequest)(nil),          // 7: user.GetUserRequest
+	(*ListUsersRequest)(nil),        // 8: user.ListUsersRequest
+	(*ListUsersResponse)(nil),       // 9: user.ListUsersResponse
+	(*CountUsersRequest)(nil),       // 10: user.CountUsersRequest
+	(*CountUsersResponse)(nil),      // 11: user.CountUsersResponse
+	(*UpdateUserRequest)(nil),       // 12: user.UpdateUserRequest
+	(*DeleteUserRequest)(nil),       // 13: user.DeleteUserRequest
+	(*DeleteUserResponse)(nil),      // 14: user.DeleteUserResponse
+	(*RestoreUserRequest)(nil),      // 15: user.RestoreUserRequest
+	(*PurgeUserRequest)(nil),        // 16: user.PurgeUserRequest
+	(*PurgeUserResponse)(nil),       // 17: user.PurgeUserResponse
+	(*SearchUsersRequest)(nil),      // 18: user.SearchUsersRequest
+	(*UserSearchResult)(nil),        // 19: user.UserSearchResult
+	(*SearchUsersResponse)(nil),     // 20: user.SearchUsersResponse
+	(*UserResponse)(nil),            // 21: user.UserResponse
+	(*WatchUsersRequest)(nil),       // 22: user.WatchUsersRequest
+	(*UserEvent)(nil),               // 23: user.UserEvent
 }
 var file_api_grpc_user_proto_depIdxs = []int32{
-	0, // 0: user.ListUsersResponse.users:type_name -> user.User
-	0, // 1: user.UserResponse.user:type_name -> user.User
-	1, // 2: user.UserService.CreateUser:input_type -> user.CreateUserRequest
-	2, // 3: user.UserService.GetUser:input_type -> user.GetUserRequest
-	3, // 4: user.UserService.ListUsers:input_type -> user.ListUsersRequest
-	5, // 5: user.UserService.UpdateUser:input_type -> user.UpdateUserRequest
-	6, // 6: user.UserService.DeleteUser:input_type -> user.DeleteUserRequest
-	8, // 7: user.UserService.CreateUser:output_type -> user.UserResponse
-	8, // 8: user.UserService.GetUser:output_type -> user.UserResponse
-	4, // 9: user.UserService.ListUsers:output_type -> user.ListUsersResponse
-	8, // 10: user.UserService.UpdateUser:output_type -> user.UserResponse
-	7, // 11: user.UserService.DeleteUser:output_type -> user.DeleteUserResponse
-	7, // [7:12] is the sub-list for method output_type
-	2, // [2:7] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	1,  // 0: user.BulkCreateResult.status:type_name -> user.BulkCreateStatus
+	3,  // 1: user.BulkCreateResult.user:type_name -> user.User
+	5,  // 2: user.BulkCreateUsersResponse.results:type_name -> user.BulkCreateResult
+	0,  // 3: user.ListUsersRequest.total_count_mode:type_name -> user.TotalCountMode
+	3,  // 4: user.ListUsersResponse.users:type_name -> user.User
+	3,  // 5: user.UserSearchResult.user:type_name -> user.User
+	19, // 6: user.SearchUsersResponse.results:type_name -> user.UserSearchResult
+	3,  // 7: user.UserResponse.user:type_name -> user.User
+	2,  // 8: user.UserEvent.type:type_name -> user.UserEventType
+	3,  // 9: user.UserEvent.user:type_name -> user.User
+	4,  // 10: user.UserService.CreateUser:input_type -> user.CreateUserRequest
+	4,  // 11: user.UserService.BulkCreateUsers:input_type -> user.CreateUserRequest
+	7,  // 12: user.UserService.GetUser:input_type -> user.GetUserRequest
+	8,  // 13: user.UserService.ListUsers:input_type -> user.ListUsersRequest
+	12, // 14: user.UserService.UpdateUser:input_type -> user.UpdateUserRequest
+	13, // 15: user.UserService.DeleteUser:input_type -> user.DeleteUserRequest
+	15, // 16: user.UserService.RestoreUser:input_type -> user.RestoreUserRequest
+	16, // 17: user.UserService.PurgeUser:input_type -> user.PurgeUserRequest
+	18, // 18: user.UserService.SearchUsers:input_type -> user.SearchUsersRequest
+	10, // 19: user.UserService.CountUsers:input_type -> user.CountUsersRequest
+	22, // 20: user.UserService.WatchUsers:input_type -> user.WatchUsersRequest
+	21, // 21: user.UserService.CreateUser:output_type -> user.UserResponse
+	6,  // 22: user.UserService.BulkCreateUsers:output_type -> user.BulkCreateUsersResponse
+	21, // 23: user.UserService.GetUser:output_type -> user.UserResponse
+	9,  // 24: user.UserService.ListUsers:output_type -> user.ListUsersResponse
+	21, // 25: user.UserService.UpdateUser:output_type -> user.UserResponse
+	14, // 26: user.UserService.DeleteUser:output_type -> user.DeleteUserResponse
+	21, // 27: user.UserService.RestoreUser:output_type -> user.UserResponse
+	17, // 28: user.UserService.PurgeUser:output_type -> user.PurgeUserResponse
+	20, // 29: user.UserService.SearchUsers:output_type -> user.SearchUsersResponse
+	11, // 30: user.UserService.CountUsers:output_type -> user.CountUsersResponse
+	23, // 31: user.UserService.WatchUsers:output_type -> user.UserEvent
+	21, // [21:32] is the sub-list for method output_type
+	10, // [10:21] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_api_grpc_user_proto_init() }
@@ -577,19 +1700,21 @@ func file_api_grpc_user_proto_init() {
 	if File_api_grpc_user_proto != nil {
 		return
 	}
-	file_api_grpc_user_proto_msgTypes[5].OneofWrappers = []any{}
+	file_api_grpc_user_proto_msgTypes[6].OneofWrappers = []any{}
+	file_api_grpc_user_proto_msgTypes[9].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_grpc_user_proto_rawDesc), len(file_api_grpc_user_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   9,
+			NumEnums:      3,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_grpc_user_proto_goTypes,
 		DependencyIndexes: file_api_grpc_user_proto_depIdxs,
+		EnumInfos:         file_api_grpc_user_proto_enumTypes,
 		MessageInfos:      file_api_grpc_user_proto_msgTypes,
 	}.Build()
 	File_api_grpc_user_proto = out.File
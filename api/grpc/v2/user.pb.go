@@ -0,0 +1,1545 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v3.21.12
+// source: api/grpc/v2/user.proto
+
+package userv2
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// How a listing's total_count is computed
+type TotalCountMode int32
+
+const (
+	// No total count is returned
+	TotalCountMode_TOTAL_COUNT_MODE_UNSPECIFIED TotalCountMode = 0
+	// Count every matching user
+	TotalCountMode_TOTAL_COUNT_MODE_EXACT TotalCountMode = 1
+	// Use the database's estimate, which avoids a full scan on large tables
+	TotalCountMode_TOTAL_COUNT_MODE_ESTIMATED TotalCountMode = 2
+)
+
+// Enum value maps for TotalCountMode.
+var (
+	TotalCountMode_name = map[int32]string{
+		0: "TOTAL_COUNT_MODE_UNSPECIFIED",
+		1: "TOTAL_COUNT_MODE_EXACT",
+		2: "TOTAL_COUNT_MODE_ESTIMATED",
+	}
+	TotalCountMode_value = map[string]int32{
+		"TOTAL_COUNT_MODE_UNSPECIFIED": 0,
+		"TOTAL_COUNT_MODE_EXACT":       1,
+		"TOTAL_COUNT_MODE_ESTIMATED":   2,
+	}
+)
+
+func (x TotalCountMode) Enum() *TotalCountMode {
+	p := new(TotalCountMode)
+	*p = x
+	return p
+}
+
+func (x TotalCountMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TotalCountMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_grpc_v2_user_proto_enumTypes[0].Descriptor()
+}
+
+func (TotalCountMode) Type() protoreflect.EnumType {
+	return &file_api_grpc_v2_user_proto_enumTypes[0]
+}
+
+func (x TotalCountMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TotalCountMode.Descriptor instead.
+func (TotalCountMode) EnumDescriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{0}
+}
+
+// Outcome of one user in a bulk create
+type BulkCreateStatus int32
+
+const (
+	BulkCreateStatus_BULK_CREATE_STATUS_UNSPECIFIED BulkCreateStatus = 0
+	BulkCreateStatus_BULK_CREATE_STATUS_CREATED     BulkCreateStatus = 1
+	// The email was already taken, possibly by an earlier user in the stream
+	BulkCreateStatus_BULK_CREATE_STATUS_DUPLICATE BulkCreateStatus = 2
+	BulkCreateStatus_BULK_CREATE_STATUS_INVALID   BulkCreateStatus = 3
+)
+
+// Enum value maps for BulkCreateStatus.
+var (
+	BulkCreateStatus_name = map[int32]string{
+		0: "BULK_CREATE_STATUS_UNSPECIFIED",
+		1: "BULK_CREATE_STATUS_CREATED",
+		2: "BULK_CREATE_STATUS_DUPLICATE",
+		3: "BULK_CREATE_STATUS_INVALID",
+	}
+	BulkCreateStatus_value = map[string]int32{
+		"BULK_CREATE_STATUS_UNSPECIFIED": 0,
+		"BULK_CREATE_STATUS_CREATED":     1,
+		"BULK_CREATE_STATUS_DUPLICATE":   2,
+		"BULK_CREATE_STATUS_INVALID":     3,
+	}
+)
+
+func (x BulkCreateStatus) Enum() *BulkCreateStatus {
+	p := new(BulkCreateStatus)
+	*p = x
+	return p
+}
+
+func (x BulkCreateStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BulkCreateStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_grpc_v2_user_proto_enumTypes[1].Descriptor()
+}
+
+func (BulkCreateStatus) Type() protoreflect.EnumType {
+	return &file_api_grpc_v2_user_proto_enumTypes[1]
+}
+
+func (x BulkCreateStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BulkCreateStatus.Descriptor instead.
+func (BulkCreateStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{1}
+}
+
+// Kind of change a UserEvent reports
+type UserEventType int32
+
+const (
+	UserEventType_USER_EVENT_TYPE_UNSPECIFIED UserEventType = 0
+	UserEventType_USER_EVENT_TYPE_CREATED     UserEventType = 1
+	UserEventType_USER_EVENT_TYPE_UPDATED     UserEventType = 2
+	// The user was soft-deleted
+	UserEventType_USER_EVENT_TYPE_DELETED  UserEventType = 3
+	UserEventType_USER_EVENT_TYPE_RESTORED UserEventType = 4
+	// The user was permanently removed
+	UserEventType_USER_EVENT_TYPE_PURGED UserEventType = 5
+)
+
+// Enum value maps for UserEventType.
+var (
+	UserEventType_name = map[int32]string{
+		0: "USER_EVENT_TYPE_UNSPECIFIED",
+		1: "USER_EVENT_TYPE_CREATED",
+		2: "USER_EVENT_TYPE_UPDATED",
+		3: "USER_EVENT_TYPE_DELETED",
+		4: "USER_EVENT_TYPE_RESTORED",
+		5: "USER_EVENT_TYPE_PURGED",
+	}
+	UserEventType_value = map[string]int32{
+		"USER_EVENT_TYPE_UNSPECIFIED": 0,
+		"USER_EVENT_TYPE_CREATED":     1,
+		"USER_EVENT_TYPE_UPDATED":     2,
+		"USER_EVENT_TYPE_DELETED":     3,
+		"USER_EVENT_TYPE_RESTORED":    4,
+		"USER_EVENT_TYPE_PURGED":      5,
+	}
+)
+
+func (x UserEventType) Enum() *UserEventType {
+	p := new(UserEventType)
+	*p = x
+	return p
+}
+
+func (x UserEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UserEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_grpc_v2_user_proto_enumTypes[2].Descriptor()
+}
+
+func (UserEventType) Type() protoreflect.EnumType {
+	return &file_api_grpc_v2_user_proto_enumTypes[2]
+}
+
+func (x UserEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UserEventType.Descriptor instead.
+func (UserEventType) EnumDescriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{2}
+}
+
+type User struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Output only
+	Id    string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Name  string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// Output only
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Output only
+	UpdateTime *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	// Incremented by every update. In an UpdateUserRequest, a non-zero
+	// version makes the update fail with ABORTED unless it matches the
+	// user's current version.
+	Version int64 `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	// Output only; unset unless the user is soft-deleted
+	DeleteTime    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=delete_time,json=deleteTime,proto3" json:"delete_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *User) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *User) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *User) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+func (x *User) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *User) GetDeleteTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeleteTime
+	}
+	return nil
+}
+
+type CreateUserRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only email and name are used
+	User          *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateUserRequest) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type BulkCreateResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the user in the request stream
+	Index  int32            `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Status BulkCreateStatus `protobuf:"varint,2,opt,name=status,proto3,enum=user.v2.BulkCreateStatus" json:"status,omitempty"`
+	// Set when the user was created
+	User *User `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	// Why the user was not created
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkCreateResult) Reset() {
+	*x = BulkCreateResult{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCreateResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCreateResult) ProtoMessage() {}
+
+func (x *BulkCreateResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCreateResult.ProtoReflect.Descriptor instead.
+func (*BulkCreateResult) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BulkCreateResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BulkCreateResult) GetStatus() BulkCreateStatus {
+	if x != nil {
+		return x.Status
+	}
+	return BulkCreateStatus_BULK_CREATE_STATUS_UNSPECIFIED
+}
+
+func (x *BulkCreateResult) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *BulkCreateResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BulkCreateUsersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One result per streamed user, in order
+	Results       []*BulkCreateResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkCreateUsersResponse) Reset() {
+	*x = BulkCreateUsersResponse{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCreateUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCreateUsersResponse) ProtoMessage() {}
+
+func (x *BulkCreateUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCreateUsersResponse.ProtoReflect.Descriptor instead.
+func (*BulkCreateUsersResponse) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BulkCreateUsersResponse) GetResults() []*BulkCreateResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetUserRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Also returns a soft-deleted user. There is no authorization layer, so
+	// any caller may set it.
+	IncludeDeleted bool `protobuf:"varint,2,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetUserRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+// Filters shared by ListUsersRequest and CountUsersRequest
+type UserFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Also returns soft-deleted users. There is no authorization layer, so
+	// any caller may set it.
+	IncludeDeleted bool `protobuf:"varint,1,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	// Matches emails starting with email_prefix, ignoring case
+	EmailPrefix string `protobuf:"bytes,2,opt,name=email_prefix,json=emailPrefix,proto3" json:"email_prefix,omitempty"`
+	// Matches names containing name_contains, ignoring case
+	NameContains string `protobuf:"bytes,3,opt,name=name_contains,json=nameContains,proto3" json:"name_contains,omitempty"`
+	// Bounds on create_time and update_time; the after bounds are inclusive
+	// and the before bounds exclusive
+	CreateTimeAfter  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=create_time_after,json=createTimeAfter,proto3" json:"create_time_after,omitempty"`
+	CreateTimeBefore *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=create_time_before,json=createTimeBefore,proto3" json:"create_time_before,omitempty"`
+	UpdateTimeAfter  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=update_time_after,json=updateTimeAfter,proto3" json:"update_time_after,omitempty"`
+	UpdateTimeBefore *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=update_time_before,json=updateTimeBefore,proto3" json:"update_time_before,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UserFilter) Reset() {
+	*x = UserFilter{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserFilter) ProtoMessage() {}
+
+func (x *UserFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserFilter.ProtoReflect.Descriptor instead.
+func (*UserFilter) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UserFilter) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+func (x *UserFilter) GetEmailPrefix() string {
+	if x != nil {
+		return x.EmailPrefix
+	}
+	return ""
+}
+
+func (x *UserFilter) GetNameContains() string {
+	if x != nil {
+		return x.NameContains
+	}
+	return ""
+}
+
+func (x *UserFilter) GetCreateTimeAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTimeAfter
+	}
+	return nil
+}
+
+func (x *UserFilter) GetCreateTimeBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTimeBefore
+	}
+	return nil
+}
+
+func (x *UserFilter) GetUpdateTimeAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTimeAfter
+	}
+	return nil
+}
+
+func (x *UserFilter) GetUpdateTimeBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTimeBefore
+	}
+	return nil
+}
+
+type ListUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Defaults to 10
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token from the previous response; empty for the first page.
+	// The filter and order_by must not change between pages.
+	PageToken string      `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Filter    *UserFilter `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	// One of "create_time", "name" or "email", optionally followed by
+	// "desc". Defaults to "create_time desc".
+	OrderBy        string         `protobuf:"bytes,4,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	TotalCountMode TotalCountMode `protobuf:"varint,5,opt,name=total_count_mode,json=totalCountMode,proto3,enum=user.v2.TotalCountMode" json:"total_count_mode,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListUsersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetFilter() *UserFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListUsersRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetTotalCountMode() TotalCountMode {
+	if x != nil {
+		return x.TotalCountMode
+	}
+	return TotalCountMode_TOTAL_COUNT_MODE_UNSPECIFIED
+}
+
+type ListUsersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Users []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	// Empty on the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Number of users matching the filter across all pages; only set when
+	// total_count_mode asks for it
+	TotalCount    *int64 `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3,oneof" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListUsersResponse) GetTotalCount() int64 {
+	if x != nil && x.TotalCount != nil {
+		return *x.TotalCount
+	}
+	return 0
+}
+
+type CountUsersRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *UserFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Return the database's estimate instead of an exact count
+	Estimate      bool `protobuf:"varint,2,opt,name=estimate,proto3" json:"estimate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountUsersRequest) Reset() {
+	*x = CountUsersRequest{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountUsersRequest) ProtoMessage() {}
+
+func (x *CountUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountUsersRequest.ProtoReflect.Descriptor instead.
+func (*CountUsersRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CountUsersRequest) GetFilter() *UserFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *CountUsersRequest) GetEstimate() bool {
+	if x != nil {
+		return x.Estimate
+	}
+	return false
+}
+
+type CountUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountUsersResponse) Reset() {
+	*x = CountUsersResponse{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountUsersResponse) ProtoMessage() {}
+
+func (x *CountUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountUsersResponse.ProtoReflect.Descriptor instead.
+func (*CountUsersResponse) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CountUsersResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type UpdateUserRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The user to update, identified by id
+	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// The fields to update: "email", "name" or "*" for both. When unset,
+	// every non-empty field of user is updated.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserRequest) ProtoMessage() {}
+
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateUserRequest) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *UpdateUserRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type DeleteUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserRequest) Reset() {
+	*x = DeleteUserRequest{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserRequest) ProtoMessage() {}
+
+func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RestoreUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreUserRequest) Reset() {
+	*x = RestoreUserRequest{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreUserRequest) ProtoMessage() {}
+
+func (x *RestoreUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreUserRequest.ProtoReflect.Descriptor instead.
+func (*RestoreUserRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RestoreUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type PurgeUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeUserRequest) Reset() {
+	*x = PurgeUserRequest{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeUserRequest) ProtoMessage() {}
+
+func (x *PurgeUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeUserRequest.ProtoReflect.Descriptor instead.
+func (*PurgeUserRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PurgeUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type SearchUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Free text matched against names and emails, tolerating typos
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Defaults to 10; at most 100 results are returned
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersRequest) Reset() {
+	*x = SearchUsersRequest{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersRequest) ProtoMessage() {}
+
+func (x *SearchUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersRequest.ProtoReflect.Descriptor instead.
+func (*SearchUsersRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SearchUsersRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchUsersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type UserSearchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	User  *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// Relevance of the match; only comparable within one response
+	Score         float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserSearchResult) Reset() {
+	*x = UserSearchResult{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserSearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserSearchResult) ProtoMessage() {}
+
+func (x *UserSearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserSearchResult.ProtoReflect.Descriptor instead.
+func (*UserSearchResult) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UserSearchResult) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *UserSearchResult) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type SearchUsersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Best match first
+	Results       []*UserSearchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersResponse) Reset() {
+	*x = SearchUsersResponse{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersResponse) ProtoMessage() {}
+
+func (x *SearchUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersResponse.ProtoReflect.Descriptor instead.
+func (*SearchUsersResponse) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SearchUsersResponse) GetResults() []*UserSearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type WatchUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the last event received, to resume after it; empty to receive the
+	// events from now on. Fails with INVALID_ARGUMENT if the events after it
+	// are no longer retained.
+	After         string `protobuf:"bytes,1,opt,name=after,proto3" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchUsersRequest) Reset() {
+	*x = WatchUsersRequest{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchUsersRequest) ProtoMessage() {}
+
+func (x *WatchUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchUsersRequest.ProtoReflect.Descriptor instead.
+func (*WatchUsersRequest) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WatchUsersRequest) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+type UserEvent struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type   UserEventType          `protobuf:"varint,2,opt,name=type,proto3,enum=user.v2.UserEventType" json:"type,omitempty"`
+	UserId string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// The user after the change; unset for deletes and purges
+	User          *User                  `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	EventTime     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=event_time,json=eventTime,proto3" json:"event_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserEvent) Reset() {
+	*x = UserEvent{}
+	mi := &file_api_grpc_v2_user_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserEvent) ProtoMessage() {}
+
+func (x *UserEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_grpc_v2_user_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserEvent.ProtoReflect.Descriptor instead.
+func (*UserEvent) Descriptor() ([]byte, []int) {
+	return file_api_grpc_v2_user_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UserEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UserEvent) GetType() UserEventType {
+	if x != nil {
+		return x.Type
+	}
+	return UserEventType_USER_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *UserEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserEvent) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *UserEvent) GetEventTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EventTime
+	}
+	return nil
+}
+
+var File_api_grpc_v2_user_proto protoreflect.FileDescriptor
+
+const file_api_grpc_v2_user_proto_rawDesc = "" +
+	"\n" +
+	"\x16api/grpc/v2/user.proto\x12\auser.v2\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x91\x02\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12;\n" +
+	"\vcreate_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12;\n" +
+	"\vupdate_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"updateTime\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x03R\aversion\x12;\n" +
+	"\vdelete_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"deleteTime\"6\n" +
+	"\x11CreateUserRequest\x12!\n" +
+	"\x04user\x18\x01 \x01(\v2\r.user.v2.UserR\x04user\"\x94\x01\n" +
+	"\x10BulkCreateResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x121\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x19.user.v2.BulkCreateStatusR\x06status\x12!\n" +
+	"\x04user\x18\x03 \x01(\v2\r.user.v2.UserR\x04user\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"N\n" +
+	"\x17BulkCreateUsersResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.user.v2.BulkCreateResultR\aresults\"I\n" +
+	"\x0eGetUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0finclude_deleted\x18\x02 \x01(\bR\x0eincludeDeleted\"\xa1\x03\n" +
+	"\n" +
+	"UserFilter\x12'\n" +
+	"\x0finclude_deleted\x18\x01 \x01(\bR\x0eincludeDeleted\x12!\n" +
+	"\femail_prefix\x18\x02 \x01(\tR\vemailPrefix\x12#\n" +
+	"\rname_contains\x18\x03 \x01(\tR\fnameContains\x12F\n" +
+	"\x11create_time_after\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x0fcreateTimeAfter\x12H\n" +
+	"\x12create_time_before\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x10createTimeBefore\x12F\n" +
+	"\x11update_time_after\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x0fupdateTimeAfter\x12H\n" +
+	"\x12update_time_before\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x10updateTimeBefore\"\xd9\x01\n" +
+	"\x10ListUsersRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12+\n" +
+	"\x06filter\x18\x03 \x01(\v2\x13.user.v2.UserFilterR\x06filter\x12\x19\n" +
+	"\border_by\x18\x04 \x01(\tR\aorderBy\x12A\n" +
+	"\x10total_count_mode\x18\x05 \x01(\x0e2\x17.user.v2.TotalCountModeR\x0etotalCountMode\"\x96\x01\n" +
+	"\x11ListUsersResponse\x12#\n" +
+	"\x05users\x18\x01 \x03(\v2\r.user.v2.UserR\x05users\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12$\n" +
+	"\vtotal_count\x18\x03 \x01(\x03H\x00R\n" +
+	"totalCount\x88\x01\x01B\x0e\n" +
+	"\f_total_count\"\\\n" +
+	"\x11CountUsersRequest\x12+\n" +
+	"\x06filter\x18\x01 \x01(\v2\x13.user.v2.UserFilterR\x06filter\x12\x1a\n" +
+	"\bestimate\x18\x02 \x01(\bR\bestimate\"*\n" +
+	"\x12CountUsersResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\"s\n" +
+	"\x11UpdateUserRequest\x12!\n" +
+	"\x04user\x18\x01 \x01(\v2\r.user.v2.UserR\x04user\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"#\n" +
+	"\x11DeleteUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"$\n" +
+	"\x12RestoreUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\"\n" +
+	"\x10PurgeUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"@\n" +
+	"\x12SearchUsersRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"K\n" +
+	"\x10UserSearchResult\x12!\n" +
+	"\x04user\x18\x01 \x01(\v2\r.user.v2.UserR\x04user\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\"J\n" +
+	"\x13SearchUsersResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.user.v2.UserSearchResultR\aresults\")\n" +
+	"\x11WatchUsersRequest\x12\x14\n" +
+	"\x05after\x18\x01 \x01(\tR\x05after\"\xbe\x01\n" +
+	"\tUserEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12*\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x16.user.v2.UserEventTypeR\x04type\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12!\n" +
+	"\x04user\x18\x04 \x01(\v2\r.user.v2.UserR\x04user\x129\n" +
+	"\n" +
+	"event_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\teventTime*n\n" +
+	"\x0eTotalCountMode\x12 \n" +
+	"\x1cTOTAL_COUNT_MODE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16TOTAL_COUNT_MODE_EXACT\x10\x01\x12\x1e\n" +
+	"\x1aTOTAL_COUNT_MODE_ESTIMATED\x10\x02*\x98\x01\n" +
+	"\x10BulkCreateStatus\x12\"\n" +
+	"\x1eBULK_CREATE_STATUS_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aBULK_CREATE_STATUS_CREATED\x10\x01\x12 \n" +
+	"\x1cBULK_CREATE_STATUS_DUPLICATE\x10\x02\x12\x1e\n" +
+	"\x1aBULK_CREATE_STATUS_INVALID\x10\x03*\xc1\x01\n" +
+	"\rUserEventType\x12\x1f\n" +
+	"\x1bUSER_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17USER_EVENT_TYPE_CREATED\x10\x01\x12\x1b\n" +
+	"\x17USER_EVENT_TYPE_UPDATED\x10\x02\x12\x1b\n" +
+	"\x17USER_EVENT_TYPE_DELETED\x10\x03\x12\x1c\n" +
+	"\x18USER_EVENT_TYPE_RESTORED\x10\x04\x12\x1a\n" +
+	"\x16USER_EVENT_TYPE_PURGED\x10\x052\xd7\x05\n" +
+	"\vUserService\x127\n" +
+	"\n" +
+	"CreateUser\x12\x1a.user.v2.CreateUserRequest\x1a\r.user.v2.User\x12Q\n" +
+	"\x0fBulkCreateUsers\x12\x1a.user.v2.CreateUserRequest\x1a .user.v2.BulkCreateUsersResponse(\x01\x121\n" +
+	"\aGetUser\x12\x17.user.v2.GetUserRequest\x1a\r.user.v2.User\x12B\n" +
+	"\tListUsers\x12\x19.user.v2.ListUsersRequest\x1a\x1a.user.v2.ListUsersResponse\x127\n" +
+	"\n" +
+	"UpdateUser\x12\x1a.user.v2.UpdateUserRequest\x1a\r.user.v2.User\x12@\n" +
+	"\n" +
+	"DeleteUser\x12\x1a.user.v2.DeleteUserRequest\x1a\x16.google.protobuf.Empty\x129\n" +
+	"\vRestoreUser\x12\x1b.user.v2.RestoreUserRequest\x1a\r.user.v2.User\x12>\n" +
+	"\tPurgeUser\x12\x19.user.v2.PurgeUserRequest\x1a\x16.google.protobuf.Empty\x12H\n" +
+	"\vSearchUsers\x12\x1b.user.v2.SearchUsersRequest\x1a\x1c.user.v2.SearchUsersResponse\x12E\n" +
+	"\n" +
+	"CountUsers\x12\x1a.user.v2.CountUsersRequest\x1a\x1b.user.v2.CountUsersResponse\x12>\n" +
+	"\n" +
+	"WatchUsers\x12\x1a.user.v2.WatchUsersRequest\x1a\x12.user.v2.UserEvent0\x01BIZGgithub.com/alexanderbkl/golang-hexagonal-boilerplate/api/grpc/v2;userv2b\x06proto3"
+
+var (
+	file_api_grpc_v2_user_proto_rawDescOnce sync.Once
+	file_api_grpc_v2_user_proto_rawDescData []byte
+)
+
+func file_api_grpc_v2_user_proto_rawDescGZIP() []byte {
+	file_api_grpc_v2_user_proto_rawDescOnce.Do(func() {
+		file_api_grpc_v2_user_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_grpc_v2_user_proto_rawDesc), len(file_api_grpc_v2_user_proto_rawDesc)))
+	})
+	return file_api_grpc_v2_user_proto_rawDescData
+}
+
+var file_api_grpc_v2_user_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_api_grpc_v2_user_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_api_grpc_v2_user_proto_goTypes = []any{
+	(TotalCountMode)(0),             // 0: user.v2.TotalCountMode
+	(BulkCreateStatus)(0),           // 1: user.v2.BulkCreateStatus
+	(UserEventType)(0),              // 2: user.v2.UserEventType
+	(*User)(nil),                    // 3: user.v2.User
+	(*CreateUserRequest)(nil),       // 4: user.v2.CreateUserRequest
+	(*BulkCreateResult)(nil),        // 5: user.v2.BulkCreateResult
+	(*BulkCreateUsersResponse)(nil), // 6: user.v2.BulkCreateUsersResponse
+	(*GetUserRequest)(nil),          // 7: user.v2.GetUserRequest
+	(*UserFilter)(nil),              // 8: user.v2.UserFilter
+	(*ListUsersRequest)(nil),        // 9: user.v2.ListUsersRequest
+	(*ListUsersResponse)(nil),       // 10: user.v2.ListUsersResponse
+	(*CountUsersRequest)(nil),       // 11: user.v2.CountUsersRequest
+	(*CountUsersResponse)(nil),      // 12: user.v2.CountUsersResponse
+	(*UpdateUserRequest)(nil),       // 13: user.v2.UpdateUserRequest
+	(*DeleteUserRequest)(nil),       // 14: user.v2.DeleteUserRequest
+	(*RestoreUserRequest)(nil),      // 15: user.v2.RestoreUserRequest
+	(*PurgeUserRequest)(nil),        // 16: user.v2.PurgeUserRequest
+	(*SearchUsersRequest)(nil),      // 17: user.v2.SearchUsersRequest
+	(*UserSearchResult)(nil),        // 18: user.v2.UserSearchResult
+	(*SearchUsersResponse)(nil),     // 19: user.v2.SearchUsersResponse
+	(*WatchUsersRequest)(nil),       // 20: user.v2.WatchUsersRequest
+	(*UserEvent)(nil),               // 21: user.v2.UserEvent
+	(*timestamppb.Timestamp)(nil),   // 22: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),   // 23: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),           // 24: google.protobuf.Empty
+}
+var file_api_grpc_v2_user_proto_depIdxs = []int32{
+	22, // 0: user.v2.User.create_time:type_name -> google.protobuf.Timestamp
+	22, // 1: user.v2.User.update_time:type_name -> google.protobuf.Timestamp
+	22, // 2: user.v2.User.delete_time:type_name -> google.protobuf.Timestamp
+	3,  // 3: user.v2.CreateUserRequest.user:type_name -> user.v2.User
+	1,  // 4: user.v2.BulkCreateResult.status:type_name -> user.v2.BulkCreateStatus
+	3,  // 5: user.v2.BulkCreateResult.user:type_name -> user.v2.User
+	5,  // 6: user.v2.BulkCreateUsersResponse.results:type_name -> user.v2.BulkCreateResult
+	22, // 7: user.v2.UserFilter.create_time_after:type_name -> google.protobuf.Timestamp
+	22, // 8: user.v2.UserFilter.create_time_before:type_name -> google.protobuf.Timestamp
+	22, // 9: user.v2.UserFilter.update_time_after:type_name -> google.protobuf.Timestamp
+	22, // 10: user.v2.UserFilter.update_time_before:type_name -> google.protobuf.Timestamp
+	8,  // 11: user.v2.ListUsersRequest.filter:type_name -> user.v2.UserFilter
+	0,  // 12: user.v2.ListUsersRequest.total_count_mode:type_name -> user.v2.TotalCountMode
+	3,  // 13: user.v2.ListUsersResponse.users:type_name -> user.v2.User
+	8,  // 14: user.v2.CountUsersRequest.filter:type_name -> user.v2.UserFilter
+	3,  // 15: user.v2.UpdateUserRequest.user:type_name -> user.v2.User
+	23, // 16: user.v2.UpdateUserRequest.update_mask:type_name -> google.protobuf.FieldMask
+	3,  // 17: user.v2.UserSearchResult.user:type_name -> user.v2.User
+	18, // 18: user.v2.SearchUsersResponse.results:type_name -> user.v2.UserSearchResult
+	2,  // 19: user.v2.UserEvent.type:type_name -> user.v2.UserEventType
+	3,  // 20: user.v2.UserEvent.user:type_name -> user.v2.User
+	22, // 21: user.v2.UserEvent.event_time:type_name -> google.protobuf.Timestamp
+	4,  // 22: user.v2.UserService.CreateUser:input_type -> user.v2.CreateUserRequest
+	4,  // 23: user.v2.UserService.BulkCreateUsers:input_type -> user.v2.CreateUserRequest
+	7,  // 24: user.v2.UserService.GetUser:input_type -> user.v2.GetUserRequest
+	9,  // 25: user.v2.UserService.ListUsers:input_type -> user.v2.ListUsersRequest
+	13, // 26: user.v2.UserService.UpdateUser:input_type -> user.v2.UpdateUserRequest
+	14, // 27: user.v2.UserService.DeleteUser:input_type -> user.v2.DeleteUserRequest
+	15, // 28: user.v2.UserService.RestoreUser:input_type -> user.v2.RestoreUserRequest
+	16, // 29: user.v2.UserService.PurgeUser:input_type -> user.v2.PurgeUserRequest
+	17, // 30: user.v2.UserService.SearchUsers:input_type -> user.v2.SearchUsersRequest
+	11, // 31: user.v2.UserService.CountUsers:input_type -> user.v2.CountUsersRequest
+	20, // 32: user.v2.UserService.WatchUsers:input_type -> user.v2.WatchUsersRequest
+	3,  // 33: user.v2.UserService.CreateUser:output_type -> user.v2.User
+	6,  // 34: user.v2.UserService.BulkCreateUsers:output_type -> user.v2.BulkCreateUsersResponse
+	3,  // 35: user.v2.UserService.GetUser:output_type -> user.v2.User
+	10, // 36: user.v2.UserService.ListUsers:output_type -> user.v2.ListUsersResponse
+	3,  // 37: user.v2.UserService.UpdateUser:output_type -> user.v2.User
+	24, // 38: user.v2.UserService.DeleteUser:output_type -> google.protobuf.Empty
+	3,  // 39: user.v2.UserService.RestoreUser:output_type -> user.v2.User
+	24, // 40: user.v2.UserService.PurgeUser:output_type -> google.protobuf.Empty
+	19, // 41: user.v2.UserService.SearchUsers:output_type -> user.v2.SearchUsersResponse
+	12, // 42: user.v2.UserService.CountUsers:output_type -> user.v2.CountUsersResponse
+	21, // 43: user.v2.UserService.WatchUsers:output_type -> user.v2.UserEvent
+	33, // [33:44] is the sub-list for method output_type
+	22, // [22:33] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_api_grpc_v2_user_proto_init() }
+func file_api_grpc_v2_user_proto_init() {
+	if File_api_grpc_v2_user_proto != nil {
+		return
+	}
+	file_api_grpc_v2_user_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_grpc_v2_user_proto_rawDesc), len(file_api_grpc_v2_user_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_grpc_v2_user_proto_goTypes,
+		DependencyIndexes: file_api_grpc_v2_user_proto_depIdxs,
+		EnumInfos:         file_api_grpc_v2_user_proto_enumTypes,
+		MessageInfos:      file_api_grpc_v2_user_proto_msgTypes,
+	}.Build()
+	File_api_grpc_v2_user_proto = out.File
+	file_api_grpc_v2_user_proto_goTypes = nil
+	file_api_grpc_v2_user_proto_depIdxs = nil
+}
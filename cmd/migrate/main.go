@@ -8,6 +8,7 @@ import (
 	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/config"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
@@ -18,11 +19,20 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Each database driver has its own migrations directory
+	sourceURL := "file://migrations"
+	databaseURL := cfg.Database.GetDSN()
+	switch cfg.Database.Driver {
+	case "sqlite":
+		sourceURL = "file://migrations/sqlite"
+		databaseURL = "sqlite://" + cfg.Database.SQLitePath
+	case "memory":
+		fmt.Println("Nothing to migrate for the memory driver")
+		return
+	}
+
 	// Create migration instance
-	m, err := migrate.New(
-		"file://migrations",
-		cfg.Database.GetDSN(),
-	)
+	m, err := migrate.New(sourceURL, databaseURL)
 	if err != nil {
 		log.Fatalf("Failed to create migrate instance: %v", err)
 	}
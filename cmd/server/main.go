@@ -8,118 +8,231 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
 	pb "github.com/alexanderbkl/golang-hexagonal-boilerplate/api/grpc"
+	pbv2 "github.com/alexanderbkl/golang-hexagonal-boilerplate/api/grpc/v2"
 	dbadapter "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/db"
 	gqladapter "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/graphql"
 	grpcadapter "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/grpc"
+	memoryadapter "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/memory"
 	redisadapter "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/redis"
+	sqliteadapter "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/sqlite"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
 	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/services"
 	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/config"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/lifecycle"
 	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/logger"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 	grpcServer "google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
 	log := logger.New()
 	log.Info("Starting application...")
 
-	// Load configuration
-	cfg, err := config.Load()
-	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+	if err := run(log); err != nil {
+		log.Errorf("Server exited with error: %v", err)
+		os.Exit(1)
 	}
+	log.Info("Server exited")
+}
 
-	// Initialize database connection
-	log.Info("Connecting to database...")
-	dbPool, err := pgxpool.New(context.Background(), cfg.Database.GetDSN())
+// run starts the servers and blocks until they have shut down and every
+// dependency is closed
+func run(log *logger.Logger) error {
+	// Load configuration
+	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
-	}
-	defer dbPool.Close()
-
-	if err := dbPool.Ping(context.Background()); err != nil {
-		log.Fatalf("Failed to ping database: %v", err)
-	}
-	log.Info("Database connection established")
-
-	// Initialize Redis connection
-	log.Info("Connecting to Redis...")
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:     cfg.Redis.GetRedisAddr(),
-		Password: cfg.Redis.Password,
-		DB:       cfg.Redis.DB,
-	})
-	defer redisClient.Close()
-
-	if err := redisClient.Ping(context.Background()).Err(); err != nil {
-		log.Fatalf("Failed to connect to Redis: %v", err)
+		return fmt.Errorf("failed to load configuration: %w", err)
 	}
-	log.Info("Redis connection established")
-
-	// Initialize repositories
-	userRepo := dbadapter.NewPostgresRepository(dbPool)
-	cacheRepo := redisadapter.NewRedisRepository(redisClient)
 
-	// Initialize services
-	userService := services.NewUserService(userRepo, cacheRepo)
+	// Dependencies are registered with the manager as they are opened, so
+	// they are closed even if a later step fails
+	manager := lifecycle.New(cfg.Server.ShutdownTimeout, log)
+	defer manager.Close()
+
+	// Health statuses follow the dependencies registered below
+	healthSrv := health.NewServer()
+	healthMonitor := grpcadapter.NewHealthMonitor(healthSrv, cfg.GRPC.HealthCheckInterval, cfg.GRPC.HealthCheckTimeout, log)
+
+	// Initialize user repository
+	var userRepo ports.UserRepository
+	var unitOfWork ports.UnitOfWork
+	switch cfg.Database.Driver {
+	case "memory":
+		log.Info("Using in-memory user repository")
+		userRepo = memoryadapter.NewUserRepository()
+		uow, err := memoryadapter.NewUnitOfWork(userRepo)
+		if err != nil {
+			return fmt.Errorf("failed to create unit of work: %w", err)
+		}
+		unitOfWork = uow
+	case "sqlite":
+		log.Infof("Opening SQLite database %s...", cfg.Database.SQLitePath)
+		sqliteDB, err := sqliteadapter.Open(cfg.Database.SQLitePath)
+		if err != nil {
+			return fmt.Errorf("failed to open SQLite database: %w", err)
+		}
+		manager.AddCloser("SQLite database", sqliteDB.Close)
 
-	// Start gRPC server
-	go func() {
-		log.Infof("Starting gRPC server on port %s...", cfg.Server.GRPCPort)
-		lis, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.Server.GRPCPort))
+		if err := sqliteDB.Ping(); err != nil {
+			return fmt.Errorf("failed to ping SQLite database: %w", err)
+		}
+		log.Info("SQLite database opened")
+		healthMonitor.AddCheck("sqlite", sqliteDB.PingContext)
+
+		userRepo = sqliteadapter.NewSQLiteRepository(sqliteDB)
+		unitOfWork = sqliteadapter.NewSQLiteUnitOfWork(sqliteDB)
+	default:
+		log.Info("Connecting to database...")
+		dbPool, err := pgxpool.New(context.Background(), cfg.Database.GetDSN())
 		if err != nil {
-			log.Fatalf("Failed to listen: %v", err)
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		manager.AddCloser("Postgres pool", func() error {
+			dbPool.Close()
+			return nil
+		})
+
+		if err := dbPool.Ping(context.Background()); err != nil {
+			return fmt.Errorf("failed to ping database: %w", err)
 		}
+		log.Info("Database connection established")
+		healthMonitor.AddCheck("postgres", dbPool.Ping)
 
-		grpcSrv := grpcServer.NewServer()
-		pb.RegisterUserServiceServer(grpcSrv, grpcadapter.NewUserServiceServer(userService))
+		userRepo = dbadapter.NewPostgresRepository(dbPool)
+		unitOfWork = dbadapter.NewPostgresUnitOfWork(dbPool)
+	}
 
-		if err := grpcSrv.Serve(lis); err != nil {
-			log.Fatalf("Failed to serve gRPC: %v", err)
+	// Connect to Redis if the cache or the event bus uses it
+	var redisClient *redis.Client
+	if cfg.Cache.Driver != "memory" || cfg.Events.Driver == "redis" {
+		log.Info("Connecting to Redis...")
+		redisClient = redis.NewClient(&redis.Options{
+			Addr:     cfg.Redis.GetRedisAddr(),
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+		manager.AddCloser("Redis client", redisClient.Close)
+
+		if err := redisClient.Ping(context.Background()).Err(); err != nil {
+			return fmt.Errorf("failed to connect to Redis: %w", err)
 		}
-	}()
+		log.Info("Redis connection established")
+		healthMonitor.AddCheck("redis", func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		})
+	}
 
-	// Start GraphQL/HTTP server
-	log.Infof("Starting GraphQL server on port %s...", cfg.Server.HTTPPort)
+	// Initialize cache
+	var cacheRepo ports.CacheRepository
+	switch cfg.Cache.Driver {
+	case "memory":
+		log.Infof("Using in-memory cache (capacity %d)", cfg.Cache.MemoryCapacity)
+		cacheRepo = memoryadapter.NewCacheRepository(cfg.Cache.MemoryCapacity)
+	case "tiered":
+		tieredCache, err := redisadapter.NewTieredRepository(
+			redisClient,
+			memoryadapter.NewCacheRepository(cfg.Cache.MemoryCapacity),
+			cfg.Cache.LocalTTL,
+			cfg.Cache.InvalidationChannel,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to cache invalidations: %w", err)
+		}
+		manager.AddCloser("tiered cache", tieredCache.Close)
 
-	resolver := gqladapter.NewResolver(userService)
-	srv := handler.NewDefaultServer(gqladapter.NewExecutableSchema(gqladapter.Config{Resolvers: resolver}))
+		log.Info("Using tiered cache (local LRU in front of Redis)")
+		cacheRepo = tieredCache
+	default:
+		cacheRepo = redisadapter.NewRedisRepository(redisClient)
+	}
 
-	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	http.Handle("/query", srv)
+	// Initialize user change events
+	var eventBus ports.UserEventBus
+	if cfg.Events.Driver == "redis" {
+		log.Infof("Publishing user events to Redis stream %s", cfg.Events.Stream)
+		eventBus = redisadapter.NewUserEventBus(redisClient, cfg.Events.Stream, int64(cfg.Events.History))
+	} else {
+		log.Info("Publishing user events in memory; watchers only see changes made by this instance")
+		eventBus = memoryadapter.NewUserEventBus(cfg.Events.History)
+	}
 
-	httpServer := &http.Server{
-		Addr:    fmt.Sprintf(":%s", cfg.Server.HTTPPort),
-		Handler: http.DefaultServeMux,
+	// Initialize services
+	structuredLog := logger.NewStructured(cfg.GRPC.LogFormat, cfg.GRPC.LogLevel)
+	userService := services.NewUserService(
+		userRepo,
+		cacheRepo,
+		services.WithUnitOfWork(unitOfWork),
+		services.WithCacheTTL(cfg.Cache.UserTTL),
+		services.WithNegativeCacheTTL(cfg.Cache.UserNegativeTTL),
+		services.WithCacheJitter(cfg.Cache.TTLJitter),
+		services.WithEventBus(eventBus),
+		services.WithLogger(structuredLog),
+	)
+
+	// Create gRPC server
+	grpcSrv := grpcServer.NewServer(grpcadapter.ServerOptions(grpcadapter.InterceptorConfig{
+		Logger:          structuredLog,
+		AccessLog:       cfg.GRPC.AccessLog,
+		RequestIDHeader: cfg.GRPC.RequestIDHeader,
+	})...)
+	userServer := grpcadapter.NewUserServiceServer(userService, log)
+	userServerV2 := grpcadapter.NewUserServiceV2Server(userService, log)
+	pb.RegisterUserServiceServer(grpcSrv, userServer)
+	pbv2.RegisterUserServiceServer(grpcSrv, userServerV2)
+	healthpb.RegisterHealthServer(grpcSrv, healthSrv)
+	if cfg.GRPC.Reflection {
+		reflection.Register(grpcSrv)
 	}
 
-	// Start HTTP server in a goroutine
-	go func() {
-		log.Infof("GraphQL playground available at http://localhost:%s/", cfg.Server.HTTPPort)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start HTTP server: %v", err)
-		}
-	}()
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.Server.GRPCPort))
+	if err != nil {
+		return fmt.Errorf("failed to listen on gRPC port: %w", err)
+	}
+	log.Infof("Starting gRPC server on port %s...", cfg.Server.GRPCPort)
+	manager.AddServer("gRPC server", lifecycle.GRPCServer(grpcSrv, grpcListener))
 
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	log.Info("Shutting down server...")
+	// Create GraphQL/HTTP server
+	resolver := gqladapter.NewResolver(userService)
+	srv := handler.NewDefaultServer(gqladapter.NewExecutableSchema(gqladapter.Config{Resolvers: resolver}))
+	srv.SetErrorPresenter(gqladapter.NewErrorPresenter(log))
 
-	// Gracefully shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	mux := http.NewServeMux()
+	mux.Handle("/", playground.Handler("GraphQL playground", "/query"))
+	mux.Handle("/query", srv)
 
-	if err := httpServer.Shutdown(ctx); err != nil {
-		log.Errorf("Server forced to shutdown: %v", err)
+	httpListener, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.Server.HTTPPort))
+	if err != nil {
+		return fmt.Errorf("failed to listen on HTTP port: %w", err)
 	}
+	log.Infof("Starting GraphQL server on port %s...", cfg.Server.HTTPPort)
+	log.Infof("GraphQL playground available at http://localhost:%s/", cfg.Server.HTTPPort)
+	manager.AddServer("GraphQL server", lifecycle.HTTPServer(&http.Server{Handler: mux}, httpListener))
+
+	// Start health checks
+	healthCtx, stopHealthChecks := context.WithCancel(context.Background())
+	defer stopHealthChecks()
+	go healthMonitor.Run(healthCtx)
+
+	// Report NOT_SERVING first, so that probes stop routing traffic here
+	// while in-flight requests drain. WatchUsers streams never finish on
+	// their own, so they are ended for their clients to reconnect elsewhere.
+	manager.OnShutdown(func() {
+		stopHealthChecks()
+		healthMonitor.Shutdown()
+		userServer.StopWatches()
+		userServerV2.StopWatches()
+	})
 
-	log.Info("Server exited")
+	// Run until SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	return manager.Run(ctx)
 }
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+// exportColumns is the header of exported CSV files
+var exportColumns = []string{"id", "email", "name", "created_at", "updated_at", "version", "deleted_at"}
+
+// exportSort lists users oldest first, so users created during an export
+// land on its last pages instead of shifting earlier ones
+var exportSort = domain.UserSort{Field: domain.UserSortCreatedAt}
+
+func runExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "", "output format, csv or jsonl (default: from the -o extension)")
+	output := fs.String("o", "-", "output file, or - for stdout")
+	includeDeleted := fs.Bool("include-deleted", false, "also export soft-deleted users")
+	pageSize := fs.Int("page-size", 500, "number of users read per query")
+	fs.Parse(args)
+
+	if *output == "-" && *format == "" {
+		*format = formatCSV
+	}
+	f, err := detectFormat(*format, *output)
+	if err != nil {
+		return err
+	}
+	if *pageSize <= 0 {
+		return fmt.Errorf("-page-size must be positive")
+	}
+
+	userService, closeDB, err := openUserService(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	out := os.Stdout
+	if *output != "-" {
+		out, err = os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+
+	var enc userEncoder = newJSONLEncoder(out)
+	if f == formatCSV {
+		enc, err = newCSVEncoder(out)
+		if err != nil {
+			return err
+		}
+	}
+
+	filter := domain.UserFilter{IncludeDeleted: *includeDeleted}
+	exported, err := exportUsers(ctx, userService, enc, filter, *pageSize)
+	if err != nil {
+		return err
+	}
+	if *output != "-" {
+		if err := out.Close(); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d users\n", exported)
+	return nil
+}
+
+// exportUsers writes the users matching filter to enc, oldest first, and
+// returns how many it wrote. Users are read page by page so memory use does
+// not grow with the table.
+func exportUsers(ctx context.Context, userService ports.UserService, enc userEncoder, filter domain.UserFilter, pageSize int) (int, error) {
+	exported := 0
+	after := ""
+	for {
+		page, err := userService.ListUsersPage(ctx, filter, exportSort, after, pageSize)
+		if err != nil {
+			return exported, err
+		}
+		for _, user := range page.Users {
+			if err := enc.Encode(user); err != nil {
+				return exported, err
+			}
+		}
+		exported += len(page.Users)
+
+		if !page.HasNextPage() {
+			break
+		}
+		after = page.NextCursor
+	}
+	return exported, enc.Flush()
+}
+
+// userEncoder writes users one at a time through a buffer
+type userEncoder interface {
+	Encode(user *domain.User) error
+	Flush() error
+}
+
+// jsonlEncoder writes users as JSON objects, one per line
+type jsonlEncoder struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func newJSONLEncoder(w io.Writer) *jsonlEncoder {
+	bw := bufio.NewWriter(w)
+	return &jsonlEncoder{w: bw, enc: json.NewEncoder(bw)}
+}
+
+func (e *jsonlEncoder) Encode(user *domain.User) error {
+	return e.enc.Encode(user)
+}
+
+func (e *jsonlEncoder) Flush() error {
+	return e.w.Flush()
+}
+
+// csvEncoder writes users as rows of exportColumns
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func newCSVEncoder(w io.Writer) (*csvEncoder, error) {
+	enc := &csvEncoder{w: csv.NewWriter(w)}
+	if err := enc.w.Write(exportColumns); err != nil {
+		return nil, err
+	}
+	return enc, nil
+}
+
+func (e *csvEncoder) Encode(user *domain.User) error {
+	deletedAt := ""
+	if user.DeletedAt != nil {
+		deletedAt = user.DeletedAt.Format(time.RFC3339Nano)
+	}
+	return e.w.Write([]string{
+		user.ID,
+		user.Email,
+		user.Name,
+		user.CreatedAt.Format(time.RFC3339Nano),
+		user.UpdatedAt.Format(time.RFC3339Nano),
+		strconv.FormatInt(user.Version, 10),
+		deletedAt,
+	})
+}
+
+func (e *csvEncoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/memory"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+)
+
+func TestExportUsers(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		created := start.Add(time.Duration(5-i) * time.Hour)
+		user := &domain.User{
+			ID:        fmt.Sprint(i),
+			Email:     fmt.Sprintf("user%d@example.com", i),
+			Name:      fmt.Sprintf("User %d", i),
+			CreatedAt: created,
+			UpdatedAt: created,
+			Version:   1,
+		}
+		if err := repo.Create(ctx, user); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	if err := repo.Delete(ctx, "2"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	service := newUserService(repo, nil, nil)
+
+	tests := []struct {
+		name           string
+		format         string
+		includeDeleted bool
+		pageSize       int
+		want           []string
+	}{
+		{"CSV", formatCSV, false, 2, []string{"4", "3", "1", "0"}},
+		{"CSVOnePage", formatCSV, false, 10, []string{"4", "3", "1", "0"}},
+		{"CSVIncludingDeleted", formatCSV, true, 2, []string{"4", "3", "2", "1", "0"}},
+		{"JSONL", formatJSONL, false, 3, []string{"4", "3", "1", "0"}},
+		{"JSONLIncludingDeleted", formatJSONL, true, 1, []string{"4", "3", "2", "1", "0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			var enc userEncoder = newJSONLEncoder(&out)
+			if tt.format == formatCSV {
+				var err error
+				if enc, err = newCSVEncoder(&out); err != nil {
+					t.Fatalf("newCSVEncoder() error = %v", err)
+				}
+			}
+
+			exported, err := exportUsers(ctx, service, enc, domain.UserFilter{IncludeDeleted: tt.includeDeleted}, tt.pageSize)
+			if err != nil {
+				t.Fatalf("exportUsers() error = %v", err)
+			}
+			if exported != len(tt.want) {
+				t.Errorf("exportUsers() = %d, want %d", exported, len(tt.want))
+			}
+
+			var ids []string
+			if tt.format == formatCSV {
+				records, err := csv.NewReader(&out).ReadAll()
+				if err != nil {
+					t.Fatalf("reading the export: %v", err)
+				}
+				if !slices.Equal(records[0], exportColumns) {
+					t.Errorf("header = %v, want %v", records[0], exportColumns)
+				}
+				for _, record := range records[1:] {
+					ids = append(ids, record[0])
+					if deleted := record[6] != ""; deleted != (record[0] == "2") {
+						t.Errorf("user %s has deleted_at %q", record[0], record[6])
+					}
+				}
+			} else {
+				for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+					var user domain.User
+					if err := json.Unmarshal([]byte(line), &user); err != nil {
+						t.Fatalf("reading the export: %v", err)
+					}
+					ids = append(ids, user.ID)
+				}
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("exported users %v, want %v oldest first", ids, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,422 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+// reportColumns is the header of the error report
+var reportColumns = []string{"line", "email", "name", "status", "error"}
+
+// importRow is one user read from an import file. Err is set when the row
+// could not be parsed.
+type importRow struct {
+	Line  int
+	Input domain.CreateUserInput
+	Err   error
+}
+
+// errDuplicateRow stops an import that does not skip duplicates
+var errDuplicateRow = errors.New("email is already taken (pass -skip-duplicates to skip such rows)")
+
+// importStats counts the outcome of the rows of an import
+type importStats struct {
+	created    int
+	duplicates int
+	invalid    int
+}
+
+func runImport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: usersctl import [flags] <file|->")
+		fmt.Fprintln(fs.Output(), "\nCSV files need a header with email and name columns; other columns are ignored.")
+		fmt.Fprintln(fs.Output(), "JSON Lines files hold one {\"email\": ..., \"name\": ...} object per line.")
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", "", "input format, csv or jsonl (default: from the file extension)")
+	batchSize := fs.Int("batch-size", 500, fmt.Sprintf("number of users created per batch, at most %d", domain.MaxBulkCreateUsers))
+	dryRun := fs.Bool("dry-run", false, "validate the file and report what would happen without creating users")
+	skipDuplicates := fs.Bool("skip-duplicates", false, "skip users whose email is taken instead of stopping the import before their batch")
+	reportPath := fs.String("errors", "", "write rows that were not imported to this CSV file")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	input := fs.Arg(0)
+	if *batchSize <= 0 || *batchSize > domain.MaxBulkCreateUsers {
+		return fmt.Errorf("-batch-size must be between 1 and %d", domain.MaxBulkCreateUsers)
+	}
+	f, err := detectFormat(*format, input)
+	if err != nil {
+		return err
+	}
+
+	in := os.Stdin
+	if input != "-" {
+		in, err = os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+	}
+	var rows rowReader
+	if f == formatCSV {
+		rows, err = newCSVRowReader(in)
+		if err != nil {
+			return err
+		}
+	} else {
+		rows = newJSONLRowReader(in)
+	}
+
+	var report *csv.Writer
+	if *reportPath != "" {
+		reportFile, err := os.Create(*reportPath)
+		if err != nil {
+			return err
+		}
+		defer reportFile.Close()
+		report = csv.NewWriter(reportFile)
+		if err := report.Write(reportColumns); err != nil {
+			return err
+		}
+	}
+
+	userService, closeDB, err := openUserService(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	imp := newImporter(userService, *dryRun, *skipDuplicates, report)
+	err = imp.run(ctx, rows, *batchSize)
+	if report != nil {
+		report.Flush()
+		err = errors.Join(err, report.Error())
+	}
+
+	verb := "Created"
+	if *dryRun {
+		verb = "Dry run: would create"
+	}
+	fmt.Fprintf(os.Stderr, "%s %d users, %d duplicates, %d invalid rows\n",
+		verb, imp.stats.created, imp.stats.duplicates, imp.stats.invalid)
+
+	if err != nil {
+		return err
+	}
+	if failed := imp.failed(); failed > 0 {
+		return fmt.Errorf("%d rows were not imported", failed)
+	}
+	return nil
+}
+
+// importer creates batches of rows and records the outcome of each. Unless
+// it skips duplicates, it stops at the first batch holding an email that is
+// already taken, without creating that batch.
+type importer struct {
+	userService    ports.UserService
+	dryRun         bool
+	skipDuplicates bool
+	report         *csv.Writer
+	stats          importStats
+	// seen holds the emails of the file checked so far
+	seen map[string]bool
+}
+
+// newImporter creates an importer writing the rows that were not imported
+// to report, if it is not nil
+func newImporter(userService ports.UserService, dryRun, skipDuplicates bool, report *csv.Writer) *importer {
+	return &importer{
+		userService:    userService,
+		dryRun:         dryRun,
+		skipDuplicates: skipDuplicates,
+		report:         report,
+		seen:           make(map[string]bool),
+	}
+}
+
+// run imports every row of rows in batches of batchSize
+func (imp *importer) run(ctx context.Context, rows rowReader, batchSize int) error {
+	batch := make([]importRow, 0, batchSize)
+	for {
+		row, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		batch = append(batch, row)
+		if len(batch) == batchSize {
+			if err := imp.importBatch(ctx, batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	return imp.importBatch(ctx, batch)
+}
+
+// failed returns the number of rows that count as failures
+func (imp *importer) failed() int {
+	failed := imp.stats.invalid
+	if !imp.skipDuplicates {
+		failed += imp.stats.duplicates
+	}
+	return failed
+}
+
+func (imp *importer) importBatch(ctx context.Context, batch []importRow) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	if imp.dryRun || !imp.skipDuplicates {
+		outcomes, err := imp.checkBatch(ctx, batch)
+		if err != nil {
+			return err
+		}
+		if imp.dryRun {
+			for i, outcome := range outcomes {
+				if err := imp.record(batch[i], outcome.status, outcome.message); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		for i, outcome := range outcomes {
+			if outcome.status == domain.BulkCreateDuplicate {
+				if err := imp.record(batch[i], outcome.status, outcome.message); err != nil {
+					return err
+				}
+				return fmt.Errorf("line %d: %w; its batch was not created", batch[i].Line, errDuplicateRow)
+			}
+		}
+	}
+
+	// Rows that failed to parse never reach the service
+	rows := make([]importRow, 0, len(batch))
+	inputs := make([]*domain.CreateUserInput, 0, len(batch))
+	for i, row := range batch {
+		if row.Err != nil {
+			if err := imp.record(row, domain.BulkCreateInvalid, row.Err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+		rows = append(rows, row)
+		inputs = append(inputs, &batch[i].Input)
+	}
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	results, err := imp.userService.BulkCreateUsers(ctx, inputs)
+	if err != nil {
+		return err
+	}
+	duplicate := 0
+	for i, result := range results {
+		if err := imp.record(rows[i], result.Status, result.Error); err != nil {
+			return err
+		}
+		if result.Status == domain.BulkCreateDuplicate && duplicate == 0 {
+			duplicate = rows[i].Line
+		}
+	}
+	// An email taken since the batch was checked is only found on creation
+	if duplicate != 0 && !imp.skipDuplicates {
+		return fmt.Errorf("line %d: %w", duplicate, errDuplicateRow)
+	}
+	return nil
+}
+
+// rowOutcome is the outcome checkBatch expects for a row
+type rowOutcome struct {
+	status  domain.BulkCreateStatus
+	message string
+}
+
+// checkBatch validates rows without creating them, looking for emails taken
+// by existing users, with one lookup per batch, or by earlier rows of the
+// file
+func (imp *importer) checkBatch(ctx context.Context, batch []importRow) ([]rowOutcome, error) {
+	var emails []string
+	for i := range batch {
+		// Check the values the service would store
+		batch[i].Input = batch[i].Input.Normalize()
+		if batch[i].Err == nil && !imp.seen[batch[i].Input.Email] {
+			emails = append(emails, batch[i].Input.Email)
+		}
+	}
+	taken := make(map[string]bool)
+	if len(emails) > 0 {
+		found, err := imp.userService.TakenEmails(ctx, emails)
+		if err != nil {
+			return nil, err
+		}
+		for _, email := range found {
+			taken[email] = true
+		}
+	}
+
+	outcomes := make([]rowOutcome, len(batch))
+	for i, row := range batch {
+		outcome := rowOutcome{status: domain.BulkCreateCreated}
+		if row.Err != nil {
+			outcome = rowOutcome{domain.BulkCreateInvalid, row.Err.Error()}
+		} else if err := row.Input.Validate(); err != nil {
+			outcome = rowOutcome{domain.BulkCreateInvalid, err.Error()}
+		} else {
+			if imp.seen[row.Input.Email] || taken[row.Input.Email] {
+				outcome = rowOutcome{domain.BulkCreateDuplicate, domain.ErrUserAlreadyExists.Error()}
+			}
+			imp.seen[row.Input.Email] = true
+		}
+		outcomes[i] = outcome
+	}
+	return outcomes, nil
+}
+
+// record counts the outcome of row and adds it to the report if it was not
+// imported
+func (imp *importer) record(row importRow, status domain.BulkCreateStatus, message string) error {
+	switch status {
+	case domain.BulkCreateCreated:
+		imp.stats.created++
+		return nil
+	case domain.BulkCreateDuplicate:
+		imp.stats.duplicates++
+	default:
+		imp.stats.invalid++
+	}
+
+	if imp.report == nil {
+		return nil
+	}
+	return imp.report.Write([]string{
+		strconv.Itoa(row.Line),
+		row.Input.Email,
+		row.Input.Name,
+		string(status),
+		message,
+	})
+}
+
+// rowReader reads the rows of an import file, returning io.EOF after the
+// last one
+type rowReader interface {
+	Next() (importRow, error)
+}
+
+// csvRowReader reads users from a CSV file with a header row
+type csvRowReader struct {
+	r     *csv.Reader
+	email int
+	name  int
+}
+
+func newCSVRowReader(r io.Reader) (*csvRowReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("the CSV file is empty")
+		}
+		return nil, err
+	}
+	reader := &csvRowReader{r: cr, email: -1, name: -1}
+	for i, column := range header {
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case "email":
+			reader.email = i
+		case "name":
+			reader.name = i
+		}
+	}
+	if reader.email < 0 || reader.name < 0 {
+		return nil, fmt.Errorf("the CSV header must have email and name columns")
+	}
+	return reader, nil
+}
+
+func (r *csvRowReader) Next() (importRow, error) {
+	record, err := r.r.Read()
+	if errors.Is(err, io.EOF) {
+		return importRow{}, err
+	}
+
+	// A malformed row is reported and skipped; the reader resumes at the
+	// next line
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return importRow{Line: parseErr.StartLine, Err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return importRow{}, err
+	}
+
+	line, _ := r.r.FieldPos(0)
+	row := importRow{Line: line}
+	if len(record) <= max(r.email, r.name) {
+		row.Err = fmt.Errorf("expected at least %d columns, got %d", max(r.email, r.name)+1, len(record))
+		return row, nil
+	}
+	row.Input.Email = strings.TrimSpace(record[r.email])
+	row.Input.Name = strings.TrimSpace(record[r.name])
+	return row, nil
+}
+
+// jsonlRowReader reads users from a file with one JSON object per line
+type jsonlRowReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+func newJSONLRowReader(r io.Reader) *jsonlRowReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	return &jsonlRowReader{s: s}
+}
+
+func (r *jsonlRowReader) Next() (importRow, error) {
+	for r.s.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.s.Text())
+		if text == "" {
+			continue
+		}
+
+		row := importRow{Line: r.line}
+		if err := json.Unmarshal([]byte(text), &row.Input); err != nil {
+			row.Err = fmt.Errorf("invalid JSON: %w", err)
+		}
+		row.Input.Email = strings.TrimSpace(row.Input.Email)
+		row.Input.Name = strings.TrimSpace(row.Input.Name)
+		return row, nil
+	}
+	if err := r.s.Err(); err != nil {
+		return importRow{}, err
+	}
+	return importRow{}, io.EOF
+}
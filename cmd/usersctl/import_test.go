@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/memory"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+// readRows reads every row of rows
+func readRows(t *testing.T, rows rowReader) []importRow {
+	t.Helper()
+	var all []importRow
+	for {
+		row, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			return all
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		all = append(all, row)
+	}
+}
+
+// wantRow is the expected line, input and parse failure of a row
+type wantRow struct {
+	line  int
+	email string
+	name  string
+	err   bool
+}
+
+func assertRows(t *testing.T, got []importRow, want []wantRow) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("read %d rows, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Line != w.line || g.Input.Email != w.email || g.Input.Name != w.name || (g.Err != nil) != w.err {
+			t.Errorf("row %d = %+v, want %+v", i, g, w)
+		}
+	}
+}
+
+func TestCSVRowReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []wantRow
+		wantErr string
+	}{
+		{
+			name:  "Header",
+			input: "email,name\nann@example.com,Ann\n",
+			want:  []wantRow{{line: 2, email: "ann@example.com", name: "Ann"}},
+		},
+		{
+			name:  "HeaderIgnoresCaseOrderAndOtherColumns",
+			input: "id, Name ,EMAIL\n1,Ann,ann@example.com\n2, Ben , ben@example.com \n",
+			want: []wantRow{
+				{line: 2, email: "ann@example.com", name: "Ann"},
+				{line: 3, email: "ben@example.com", name: "Ben"},
+			},
+		},
+		{
+			name:  "ShortAndMalformedRowsAreReported",
+			input: "email,name\nann@example.com\n\"ben,Ben\nnot-read",
+			want: []wantRow{
+				{line: 2, email: "", name: "", err: true},
+				{line: 3, err: true},
+			},
+		},
+		{
+			name:  "HeaderOnly",
+			input: "email,name\n",
+		},
+		{
+			name:    "Empty",
+			input:   "",
+			wantErr: "empty",
+		},
+		{
+			name:    "MissingColumn",
+			input:   "email,full_name\nann@example.com,Ann\n",
+			wantErr: "email and name columns",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := newCSVRowReader(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("newCSVRowReader() error = %v, want one mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newCSVRowReader() error = %v", err)
+			}
+			assertRows(t, readRows(t, rows), tt.want)
+		})
+	}
+}
+
+func TestJSONLRowReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []wantRow
+	}{
+		{
+			name:  "Objects",
+			input: "{\"email\": \" ann@example.com \", \"name\": \"Ann\"}\n{\"email\": \"ben@example.com\", \"name\": \"Ben\", \"id\": \"1\"}",
+			want: []wantRow{
+				{line: 1, email: "ann@example.com", name: "Ann"},
+				{line: 2, email: "ben@example.com", name: "Ben"},
+			},
+		},
+		{
+			name:  "BlankLinesAreSkipped",
+			input: "\n{\"email\": \"ann@example.com\", \"name\": \"Ann\"}\n  \n",
+			want:  []wantRow{{line: 2, email: "ann@example.com", name: "Ann"}},
+		},
+		{
+			name:  "InvalidJSONIsReported",
+			input: "{\"email\": \nnot json\n{\"email\": \"ann@example.com\", \"name\": \"Ann\"}",
+			want: []wantRow{
+				{line: 1, err: true},
+				{line: 2, err: true},
+				{line: 3, email: "ann@example.com", name: "Ann"},
+			},
+		},
+		{
+			name: "Empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertRows(t, readRows(t, newJSONLRowReader(strings.NewReader(tt.input))), tt.want)
+		})
+	}
+}
+
+// sliceRowReader returns rows in order
+type sliceRowReader []importRow
+
+func (r *sliceRowReader) Next() (importRow, error) {
+	if len(*r) == 0 {
+		return importRow{}, io.EOF
+	}
+	row := (*r)[0]
+	*r = (*r)[1:]
+	return row, nil
+}
+
+// countingService counts the TakenEmails calls reaching a user service
+type countingService struct {
+	ports.UserService
+	takenEmailsCalls int
+}
+
+func (s *countingService) TakenEmails(ctx context.Context, emails []string) ([]string, error) {
+	s.takenEmailsCalls++
+	return s.UserService.TakenEmails(ctx, emails)
+}
+
+// newTestService returns a user service over an in-memory repository holding
+// one user with the email taken@example.com
+func newTestService(t *testing.T) (*countingService, ports.UserRepository) {
+	t.Helper()
+	repo := memory.NewUserRepository()
+	uow, err := memory.NewUnitOfWork(repo)
+	if err != nil {
+		t.Fatalf("NewUnitOfWork() error = %v", err)
+	}
+	service := newUserService(repo, uow, nil)
+	if _, err := service.CreateUser(context.Background(), &domain.CreateUserInput{Email: "taken@example.com", Name: "Taken"}); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	return &countingService{UserService: service}, repo
+}
+
+func TestImporter(t *testing.T) {
+	rows := []importRow{
+		{Line: 2, Input: domain.CreateUserInput{Email: "Ann@Example.com", Name: "Ann"}},
+		{Line: 3, Input: domain.CreateUserInput{Email: "taken@example.com", Name: "Taken again"}},
+		{Line: 4, Input: domain.CreateUserInput{Email: "ann@example.com", Name: "Ann again"}},
+		{Line: 5, Input: domain.CreateUserInput{Email: "not-an-email", Name: "Bad"}},
+		{Line: 6, Err: errors.New("unreadable")},
+		{Line: 7, Input: domain.CreateUserInput{Email: "ben@example.com", Name: "Ben"}},
+	}
+	tests := []struct {
+		name           string
+		dryRun         bool
+		skipDuplicates bool
+		wantErr        error
+		wantStats      importStats
+		wantReported   []string
+		wantFailed     int
+		wantUsers      int64
+	}{
+		{
+			name:         "Import",
+			wantErr:      errDuplicateRow,
+			wantStats:    importStats{duplicates: 1},
+			wantReported: []string{"3"},
+			wantFailed:   1,
+			wantUsers:    1,
+		},
+		{
+			name:           "ImportSkippingDuplicates",
+			skipDuplicates: true,
+			wantStats:      importStats{created: 2, duplicates: 2, invalid: 2},
+			wantReported:   []string{"3", "4", "5", "6"},
+			wantFailed:     2,
+			wantUsers:      3,
+		},
+		{
+			name:         "DryRun",
+			dryRun:       true,
+			wantStats:    importStats{created: 2, duplicates: 2, invalid: 2},
+			wantReported: []string{"3", "4", "5", "6"},
+			wantFailed:   4,
+			wantUsers:    1,
+		},
+		{
+			name:           "DryRunSkippingDuplicates",
+			dryRun:         true,
+			skipDuplicates: true,
+			wantStats:      importStats{created: 2, duplicates: 2, invalid: 2},
+			wantReported:   []string{"3", "4", "5", "6"},
+			wantFailed:     2,
+			wantUsers:      1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service, repo := newTestService(t)
+			var report strings.Builder
+			reportWriter := csv.NewWriter(&report)
+
+			imp := newImporter(service, tt.dryRun, tt.skipDuplicates, reportWriter)
+			input := sliceRowReader(slices.Clone(rows))
+			if err := imp.run(ctx, &input, 4); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("run() error = %v, want %v", err, tt.wantErr)
+			}
+			reportWriter.Flush()
+
+			if imp.stats != tt.wantStats {
+				t.Errorf("stats = %+v, want %+v", imp.stats, tt.wantStats)
+			}
+			if got := imp.failed(); got != tt.wantFailed {
+				t.Errorf("failed() = %d, want %d", got, tt.wantFailed)
+			}
+			records, err := csv.NewReader(strings.NewReader(report.String())).ReadAll()
+			if err != nil {
+				t.Fatalf("reading the report: %v", err)
+			}
+			var lines []string
+			for _, record := range records {
+				lines = append(lines, record[0])
+			}
+			if !slices.Equal(lines, tt.wantReported) {
+				t.Errorf("reported lines = %v, want %v", lines, tt.wantReported)
+			}
+			if count, err := repo.Count(ctx, domain.UserFilter{}); err != nil || count != tt.wantUsers {
+				t.Errorf("Count() = %d, %v, want %d", count, err, tt.wantUsers)
+			}
+		})
+	}
+}
+
+func TestImportStopsBeforeBatchWithDuplicate(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestService(t)
+	rows := sliceRowReader{
+		{Line: 2, Input: domain.CreateUserInput{Email: "ann@example.com", Name: "Ann"}},
+		{Line: 3, Input: domain.CreateUserInput{Email: "ben@example.com", Name: "Ben"}},
+		{Line: 4, Input: domain.CreateUserInput{Email: "cy@example.com", Name: "Cy"}},
+		{Line: 5, Input: domain.CreateUserInput{Email: "ann@example.com", Name: "Ann again"}},
+		{Line: 6, Input: domain.CreateUserInput{Email: "dee@example.com", Name: "Dee"}},
+	}
+
+	imp := newImporter(service, false, false, nil)
+	err := imp.run(ctx, &rows, 2)
+	if !errors.Is(err, errDuplicateRow) || !strings.HasPrefix(err.Error(), "line 5:") {
+		t.Fatalf("run() error = %v, want %v at line 5", err, errDuplicateRow)
+	}
+	// Only the first batch, and the user the service started with, exist
+	if count, err := repo.Count(ctx, domain.UserFilter{}); err != nil || count != 3 {
+		t.Errorf("Count() = %d, %v, want 3", count, err)
+	}
+	if len(rows) != 1 {
+		t.Errorf("%d rows were left unread, want 1", len(rows))
+	}
+}
+
+func TestDryRunLooksUpEmailsOncePerBatch(t *testing.T) {
+	service, _ := newTestService(t)
+	var rows sliceRowReader
+	for i := range 10 {
+		rows = append(rows, importRow{Line: i + 1, Input: domain.CreateUserInput{Email: fmt.Sprintf("user%d@example.com", i), Name: "User"}})
+	}
+
+	imp := newImporter(service, true, false, nil)
+	if err := imp.run(context.Background(), &rows, 4); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if service.takenEmailsCalls != 3 {
+		t.Errorf("TakenEmails() was called %d times for 3 batches, want 3", service.takenEmailsCalls)
+	}
+	if imp.stats.created != 10 {
+		t.Errorf("stats = %+v, want 10 created", imp.stats)
+	}
+}
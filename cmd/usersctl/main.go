@@ -0,0 +1,162 @@
+// Command usersctl exports and imports users in bulk. Imports go through the
+// user service, so they are validated like any other write.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	dbadapter "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/db"
+	redisadapter "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/redis"
+	sqliteadapter "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/sqlite"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/services"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/config"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+const usage = `Usage: usersctl <command> [flags]
+
+Commands:
+  export    Write every user to a CSV or JSON Lines file
+  import    Create users from a CSV or JSON Lines file
+
+Run "usersctl <command> -h" for the flags of a command.
+`
+
+// Supported file formats
+const (
+	formatCSV   = "csv"
+	formatJSONL = "jsonl"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = runExport(ctx, os.Args[2:])
+	case "import":
+		err = runImport(ctx, os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "usersctl %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+// openUserService connects to the configured database and returns a user
+// service without a cache, along with a function that closes the connection
+func openUserService(ctx context.Context) (ports.UserService, func(), error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	var repo ports.UserRepository
+	var uow ports.UnitOfWork
+	var closeDB func()
+	switch cfg.Database.Driver {
+	case "memory":
+		return nil, nil, fmt.Errorf("the memory driver keeps no data between runs; use postgres or sqlite")
+	case "sqlite":
+		db, err := sqliteadapter.Open(cfg.Database.SQLitePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open SQLite database: %w", err)
+		}
+		if err := db.PingContext(ctx); err != nil {
+			db.Close()
+			return nil, nil, fmt.Errorf("failed to ping SQLite database: %w", err)
+		}
+		repo, uow, closeDB = sqliteadapter.NewSQLiteRepository(db), sqliteadapter.NewSQLiteUnitOfWork(db), closer(db)
+	default:
+		pool, err := pgxpool.New(ctx, cfg.Database.GetDSN())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		if err := pool.Ping(ctx); err != nil {
+			pool.Close()
+			return nil, nil, fmt.Errorf("failed to ping database: %w", err)
+		}
+		repo, uow, closeDB = dbadapter.NewPostgresRepository(pool), dbadapter.NewPostgresUnitOfWork(pool), pool.Close
+	}
+
+	eventBus, closeEvents := openEventBus(ctx, cfg)
+	return newUserService(repo, uow, eventBus), func() {
+		closeEvents()
+		closeDB()
+	}, nil
+}
+
+// openEventBus connects to the Redis event stream, so that the servers'
+// WatchUsers streams report imported users. Without Redis, imports still
+// work but publish no events.
+func openEventBus(ctx context.Context, cfg *config.Config) (ports.UserEventBus, func()) {
+	if cfg.Events.Driver != "redis" {
+		return nil, func() {}
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.GetRedisAddr(),
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		fmt.Fprintf(os.Stderr, "usersctl: not publishing user events, Redis is unavailable: %v\n", err)
+		return nil, func() {}
+	}
+	return redisadapter.NewUserEventBus(client, cfg.Events.Stream, int64(cfg.Events.History)), func() { client.Close() }
+}
+
+// newUserService creates a user service without a cache. Imported users are
+// never cached, so a running server's cache stays consistent. A nil eventBus
+// publishes no events.
+func newUserService(repo ports.UserRepository, uow ports.UnitOfWork, eventBus ports.UserEventBus) ports.UserService {
+	// Failures to publish events are reported on stderr with the summary
+	log := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	return services.NewUserService(repo, nil, services.WithUnitOfWork(uow), services.WithEventBus(eventBus), services.WithLogger(log))
+}
+
+func closer(db *sql.DB) func() {
+	return func() { db.Close() }
+}
+
+// detectFormat returns format if set, or guesses it from the file extension
+func detectFormat(format, path string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = formatCSV
+		case ".jsonl", ".ndjson":
+			format = formatJSONL
+		default:
+			return "", fmt.Errorf("cannot tell the format of %q; set -format", path)
+		}
+	}
+	if format != formatCSV && format != formatJSONL {
+		return "", fmt.Errorf("unknown format %q; use csv or jsonl", format)
+	}
+	return format, nil
+}
@@ -0,0 +1,74 @@
+-- name: CreateUser :one
+INSERT INTO users (id, email, name, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: CreateUserIfAbsent :execrows
+INSERT INTO users (id, email, name, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT DO NOTHING;
+
+-- name: GetUserByID :one
+SELECT * FROM users
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: GetUserByIDIncludingDeleted :one
+SELECT * FROM users
+WHERE id = ?;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = ? AND deleted_at IS NULL;
+
+-- name: TakenEmails :many
+-- The emails are a JSON array, which goes through a CTE because sqlc does
+-- not bind parameters in table-valued functions for SQLite
+WITH taken AS (
+  SELECT CAST(sqlc.arg(emails) AS TEXT) AS emails
+)
+SELECT users.email FROM users, taken
+WHERE users.email IN (SELECT value FROM json_each(taken.emails));
+
+-- name: SearchCandidates :many
+-- Users whose lowercased name or email contains any of the JSON array of
+-- fragments, newest first; they are ranked in Go as SQLite lacks pg_trgm.
+-- The fragments go through a CTE because sqlc does not bind parameters in
+-- table-valued functions for SQLite.
+WITH search AS (
+  SELECT CAST(sqlc.arg(fragments) AS TEXT) AS fragments
+)
+SELECT users.* FROM users, search
+WHERE deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM json_each(search.fragments) AS fragment
+    WHERE instr(lower(users.name), fragment.value) > 0
+       OR instr(lower(users.email), fragment.value) > 0
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: UpdateUser :one
+UPDATE users
+SET email = ?,
+    name = ?,
+    updated_at = ?,
+    version = version + 1
+WHERE id = ? AND version = ? AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = ?,
+    version = version + 1
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL,
+    version = version + 1
+WHERE id = ? AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeUser :execrows
+DELETE FROM users
+WHERE id = ?;
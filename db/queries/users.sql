@@ -5,25 +5,61 @@ RETURNING *;
 
 -- name: GetUserByID :one
 SELECT * FROM users
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetUserByIDIncludingDeleted :one
+SELECT * FROM users
 WHERE id = $1;
 
--- name: GetUserByEmail :one
+-- name: GetUserByIDForUpdate :one
 SELECT * FROM users
-WHERE email = $1;
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE;
 
--- name: ListUsers :many
+-- name: GetUserByEmail :one
 SELECT * FROM users
-ORDER BY created_at DESC
-LIMIT $1 OFFSET $2;
+WHERE email = $1 AND deleted_at IS NULL;
+
+-- name: TakenEmails :many
+SELECT email FROM users
+WHERE email = ANY(sqlc.arg(emails)::text[]);
 
 -- name: UpdateUser :one
 UPDATE users
 SET email = $2,
     name = $3,
-    updated_at = $4
-WHERE id = $1
+    updated_at = $4,
+    version = version + 1
+WHERE id = $1 AND version = $5 AND deleted_at IS NULL
 RETURNING *;
 
--- name: DeleteUser :exec
+-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = $2,
+    version = version + 1
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL,
+    version = version + 1
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeUser :execrows
 DELETE FROM users
 WHERE id = $1;
+
+-- name: SearchUsers :many
+-- Users match on their full-text vector or when name or email is similar to
+-- the query by at least pg_trgm.similarity_threshold (0.3 by default)
+SELECT sqlc.embed(users),
+       (ts_rank(search_vector, plainto_tsquery('simple', sqlc.arg(query)::text))
+        + greatest(similarity(name, sqlc.arg(query)::text), similarity(email, sqlc.arg(query)::text)))::float8 AS score
+FROM users
+WHERE deleted_at IS NULL
+  AND (search_vector @@ plainto_tsquery('simple', sqlc.arg(query)::text)
+    OR name % sqlc.arg(query)::text
+    OR email % sqlc.arg(query)::text)
+ORDER BY score DESC, id
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes translated to domain errors, see
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	foreignKeyViolation  = "23503"
+	uniqueViolation      = "23505"
+	checkViolation       = "23514"
+	serializationFailure = "40001"
+)
+
+// translateError converts constraint violations and serialization failures
+// to domain errors. Other errors are returned unchanged.
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case uniqueViolation:
+		// The only unique constraints are the primary key and the email
+		return domain.ErrUserAlreadyExists
+	case foreignKeyViolation, checkViolation:
+		return domain.ErrInvalidInput
+	case serializationFailure:
+		return domain.ErrConflict
+	}
+	return err
+}
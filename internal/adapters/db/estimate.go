@@ -0,0 +1,31 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	sqlcdb "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/db/sqlc"
+)
+
+// estimateRows returns the planner's estimate of how many rows sql would
+// return for args, without running it
+func estimateRows(ctx context.Context, db sqlcdb.DBTX, sql string, args []any) (int64, error) {
+	var raw string
+	if err := db.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+sql, args...).Scan(&raw); err != nil {
+		return 0, err
+	}
+
+	var plans []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(raw), &plans); err != nil {
+		return 0, err
+	}
+	if len(plans) == 0 {
+		return 0, errors.New("empty query plan")
+	}
+	return int64(plans[0].Plan.Rows), nil
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	sqlcdb "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/db/sqlc"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/sqlquery"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// dialect numbers the listing query parameters as Postgres expects
+var dialect = sqlquery.Dialect{
+	Placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+	Time:        func(t time.Time) any { return toPgTimestamp(t) },
+}
+
+// listUsers runs a listing query built by sqlquery.UserQuery.ListSQL,
+// scanning its sqlquery.UserColumns
+func listUsers(ctx context.Context, db sqlcdb.DBTX, sql string, args []any) ([]*domain.User, error) {
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.User, error) {
+		var u sqlcdb.User
+		err := row.Scan(&u.ID, &u.Email, &u.Name, &u.CreatedAt, &u.UpdatedAt, &u.Version, &u.DeletedAt)
+		return toDomainUser(u), err
+	})
+}
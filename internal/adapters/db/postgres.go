@@ -5,6 +5,7 @@ import (
 	"time"
 
 	sqlcdb "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/db/sqlc"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/sqlquery"
 	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
 	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
 	"github.com/jackc/pgx/v5"
@@ -16,13 +17,16 @@ import (
 type PostgresRepository struct {
 	db      *pgxpool.Pool
 	queries *sqlcdb.Queries
+	uow     ports.UnitOfWork
 }
 
-// NewPostgresRepository creates a new PostgreSQL repository
+// NewPostgresRepository creates a new PostgreSQL repository. Calls made with
+// a context from PostgresUnitOfWork.WithinTx run in that transaction.
 func NewPostgresRepository(db *pgxpool.Pool) ports.UserRepository {
 	return &PostgresRepository{
 		db:      db,
 		queries: sqlcdb.New(db),
+		uow:     NewPostgresUnitOfWork(db),
 	}
 }
 
@@ -42,21 +46,114 @@ func fromPgTimestamp(t pgtype.Timestamp) time.Time {
 	return time.Time{}
 }
 
+// Helper function to convert a nullable pgtype.Timestamp to *time.Time
+func fromNullablePgTimestamp(t pgtype.Timestamp) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func toDomainUser(u sqlcdb.User) *domain.User {
+	return &domain.User{
+		ID:        u.ID,
+		Email:     u.Email,
+		Name:      u.Name,
+		CreatedAt: fromPgTimestamp(u.CreatedAt),
+		UpdatedAt: fromPgTimestamp(u.UpdatedAt),
+		Version:   u.Version,
+		DeletedAt: fromNullablePgTimestamp(u.DeletedAt),
+	}
+}
+
 // Create creates a new user
 func (r *PostgresRepository) Create(ctx context.Context, user *domain.User) error {
-	_, err := r.queries.CreateUser(ctx, sqlcdb.CreateUserParams{
+	_, err := queriesFor(ctx, r.queries).CreateUser(ctx, sqlcdb.CreateUserParams{
 		ID:        user.ID,
 		Email:     user.Email,
 		Name:      user.Name,
 		CreatedAt: toPgTimestamp(user.CreatedAt),
 		UpdatedAt: toPgTimestamp(user.UpdatedAt),
 	})
-	return err
+	return translateError(err)
+}
+
+// COPY aborts on the first unique violation, so bulk creates copy users into
+// a staging table and insert them from there, skipping conflicts. Rows are
+// inserted in input order so the first of two users sharing an email wins.
+const (
+	createUserImportsTable = `CREATE TEMP TABLE user_imports (
+	position INTEGER NOT NULL,
+	id VARCHAR(255) NOT NULL,
+	email VARCHAR(255) NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL
+) ON COMMIT DROP`
+
+	insertUserImports = `INSERT INTO users (id, email, name, created_at, updated_at)
+SELECT id, email, name, created_at, updated_at FROM user_imports ORDER BY position
+ON CONFLICT DO NOTHING
+RETURNING id`
+
+	dropUserImportsTable = `DROP TABLE user_imports`
+)
+
+var userImportColumns = []string{"position", "id", "email", "name", "created_at", "updated_at"}
+
+// BulkCreate creates users with COPY in one transaction, skipping those
+// whose email is taken
+func (r *PostgresRepository) BulkCreate(ctx context.Context, users []*domain.User) ([]domain.BulkCreateStatus, error) {
+	statuses := make([]domain.BulkCreateStatus, len(users))
+	err := r.uow.WithinTx(ctx, func(ctx context.Context) error {
+		tx := ctx.Value(txKey{}).(pgx.Tx)
+
+		if _, err := tx.Exec(ctx, createUserImportsTable); err != nil {
+			return err
+		}
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{"user_imports"}, userImportColumns,
+			pgx.CopyFromSlice(len(users), func(i int) ([]any, error) {
+				user := users[i]
+				return []any{i, user.ID, user.Email, user.Name, toPgTimestamp(user.CreatedAt), toPgTimestamp(user.UpdatedAt)}, nil
+			}))
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, insertUserImports)
+		if err != nil {
+			return err
+		}
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+
+		created := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			created[id] = true
+		}
+		for i, user := range users {
+			statuses[i] = domain.BulkCreateDuplicate
+			if created[user.ID] {
+				statuses[i] = domain.BulkCreateCreated
+			}
+		}
+
+		// Drop the table now in case the transaction runs another bulk create
+		_, err = tx.Exec(ctx, dropUserImportsTable)
+		return err
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return statuses, nil
 }
 
 // GetByID retrieves a user by ID
 func (r *PostgresRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
-	user, err := r.queries.GetUserByID(ctx, id)
+	user, err := queriesFor(ctx, r.queries).GetUserByID(ctx, id)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, domain.ErrUserNotFound
@@ -64,100 +161,190 @@ func (r *PostgresRepository) GetByID(ctx context.Context, id string) (*domain.Us
 		return nil, err
 	}
 
-	return &domain.User{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		CreatedAt: fromPgTimestamp(user.CreatedAt),
-		UpdatedAt: fromPgTimestamp(user.UpdatedAt),
-	}, nil
+	return toDomainUser(user), nil
+}
+
+// GetByIDIncludingDeleted retrieves a user by ID, even if soft-deleted
+func (r *PostgresRepository) GetByIDIncludingDeleted(ctx context.Context, id string) (*domain.User, error) {
+	user, err := queriesFor(ctx, r.queries).GetUserByIDIncludingDeleted(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return toDomainUser(user), nil
 }
 
 // GetByEmail retrieves a user by email
 func (r *PostgresRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
-	user, err := r.queries.GetUserByEmail(ctx, email)
+	user, err := queriesFor(ctx, r.queries).GetUserByEmail(ctx, email)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, nil
+			return nil, domain.ErrUserNotFound
 		}
 		return nil, err
 	}
 
-	return &domain.User{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		CreatedAt: fromPgTimestamp(user.CreatedAt),
-		UpdatedAt: fromPgTimestamp(user.UpdatedAt),
-	}, nil
+	return toDomainUser(user), nil
+}
+
+// TakenEmails returns those of emails that belong to a user, deleted or not
+func (r *PostgresRepository) TakenEmails(ctx context.Context, emails []string) ([]string, error) {
+	return queriesFor(ctx, r.queries).TakenEmails(ctx, emails)
 }
 
 // List retrieves a list of users
-func (r *PostgresRepository) List(ctx context.Context, limit, offset int) ([]*domain.User, error) {
-	users, err := r.queries.ListUsers(ctx, sqlcdb.ListUsersParams{
-		Limit:  int32(limit),
-		Offset: int32(offset),
+func (r *PostgresRepository) List(ctx context.Context, filter domain.UserFilter, sort domain.UserSort, limit, offset int) ([]*domain.User, error) {
+	q := sqlquery.NewUserQuery(dialect, filter)
+	sql := q.ListSQL(sort, limit, offset)
+	return listUsers(ctx, dbFor(ctx, r.db), sql, q.Args)
+}
+
+// ListAfter retrieves the users following a cursor in sort order
+func (r *PostgresRepository) ListAfter(ctx context.Context, filter domain.UserFilter, sort domain.UserSort, after *domain.UserCursor, limit int) ([]*domain.User, error) {
+	q := sqlquery.NewUserQuery(dialect, filter)
+	if after != nil {
+		q.After(sort, after)
+	}
+	sql := q.ListSQL(sort, limit, 0)
+	return listUsers(ctx, dbFor(ctx, r.db), sql, q.Args)
+}
+
+// Count counts the users matching filter
+func (r *PostgresRepository) Count(ctx context.Context, filter domain.UserFilter) (int64, error) {
+	q := sqlquery.NewUserQuery(dialect, filter)
+	var count int64
+	sql := q.SelectSQL("count(*)")
+	err := dbFor(ctx, r.db).QueryRow(ctx, sql, q.Args...).Scan(&count)
+	return count, err
+}
+
+// EstimateCount returns the query planner's estimate of the users matching
+// filter, which relies on table statistics rather than a scan. It is only
+// as fresh as the last ANALYZE of the users table.
+func (r *PostgresRepository) EstimateCount(ctx context.Context, filter domain.UserFilter) (int64, error) {
+	q := sqlquery.NewUserQuery(dialect, filter)
+	sql := q.SelectSQL("id")
+	return estimateRows(ctx, dbFor(ctx, r.db), sql, q.Args)
+}
+
+// Search ranks users by full-text rank plus trigram similarity of their
+// name or email
+func (r *PostgresRepository) Search(ctx context.Context, query string, limit int) ([]*domain.UserSearchResult, error) {
+	rows, err := queriesFor(ctx, r.queries).SearchUsers(ctx, sqlcdb.SearchUsersParams{
+		Query: query,
+		Limit: int32(limit),
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]*domain.User, len(users))
-	for i, u := range users {
-		result[i] = &domain.User{
-			ID:        u.ID,
-			Email:     u.Email,
-			Name:      u.Name,
-			CreatedAt: fromPgTimestamp(u.CreatedAt),
-			UpdatedAt: fromPgTimestamp(u.UpdatedAt),
+	results := make([]*domain.UserSearchResult, len(rows))
+	for i, row := range rows {
+		results[i] = &domain.UserSearchResult{
+			User:  toDomainUser(row.User),
+			Score: row.Score,
 		}
 	}
 
-	return result, nil
+	return results, nil
 }
 
-// Update updates a user
+// Update updates a user. The current row is locked while the new values are
+// computed so concurrent partial updates do not overwrite each other, and the
+// update fails with domain.ErrConflict if input.ExpectedVersion is stale.
 func (r *PostgresRepository) Update(ctx context.Context, id string, input *domain.UpdateUserInput) (*domain.User, error) {
-	// Get current user
-	currentUser, err := r.GetByID(ctx, id)
+	var result *domain.User
+	err := r.uow.WithinTx(ctx, func(ctx context.Context) error {
+		queries := queriesFor(ctx, r.queries)
+
+		// Get current user
+		currentUser, err := queries.GetUserByIDForUpdate(ctx, id)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return domain.ErrUserNotFound
+			}
+			return err
+		}
+
+		if input.ExpectedVersion != nil && *input.ExpectedVersion != currentUser.Version {
+			return domain.ErrConflict
+		}
+
+		// Use current values if not provided in input
+		email := currentUser.Email
+		name := currentUser.Name
+
+		if input.Email != nil {
+			email = *input.Email
+		}
+		if input.Name != nil {
+			name = *input.Name
+		}
+
+		user, err := queries.UpdateUser(ctx, sqlcdb.UpdateUserParams{
+			ID:        id,
+			Email:     email,
+			Name:      name,
+			UpdatedAt: toPgTimestamp(time.Now()),
+			Version:   currentUser.Version,
+		})
+		if err != nil {
+			// The row was locked above, so a missing row means its version moved
+			if err == pgx.ErrNoRows {
+				return domain.ErrConflict
+			}
+			return err
+		}
+
+		result = toDomainUser(user)
+		return nil
+	})
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 
-	// Use current values if not provided in input
-	email := currentUser.Email
-	name := currentUser.Name
+	return result, nil
+}
 
-	if input.Email != nil {
-		email = *input.Email
+// Delete soft-deletes a user
+func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
+	rows, err := queriesFor(ctx, r.queries).SoftDeleteUser(ctx, sqlcdb.SoftDeleteUserParams{
+		ID:        id,
+		DeletedAt: toPgTimestamp(time.Now()),
+	})
+	if err != nil {
+		return translateError(err)
 	}
-	if input.Name != nil {
-		name = *input.Name
+	if rows == 0 {
+		return domain.ErrUserNotFound
 	}
+	return nil
+}
 
-	user, err := r.queries.UpdateUser(ctx, sqlcdb.UpdateUserParams{
-		ID:        id,
-		Email:     email,
-		Name:      name,
-		UpdatedAt: toPgTimestamp(time.Now()),
-	})
+// Restore undoes a soft delete
+func (r *PostgresRepository) Restore(ctx context.Context, id string) (*domain.User, error) {
+	user, err := queriesFor(ctx, r.queries).RestoreUser(ctx, id)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, domain.ErrUserNotFound
 		}
-		return nil, err
+		return nil, translateError(err)
 	}
 
-	return &domain.User{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		CreatedAt: fromPgTimestamp(user.CreatedAt),
-		UpdatedAt: fromPgTimestamp(user.UpdatedAt),
-	}, nil
+	return toDomainUser(user), nil
 }
 
-// Delete deletes a user
-func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
-	return r.queries.DeleteUser(ctx, id)
+// Purge permanently deletes a user
+func (r *PostgresRepository) Purge(ctx context.Context, id string) error {
+	rows, err := queriesFor(ctx, r.queries).PurgeUser(ctx, id)
+	if err != nil {
+		return translateError(err)
+	}
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
 }
@@ -0,0 +1,55 @@
+package db_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	dbadapter "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/db"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports/porttest"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TestPostgresRepository runs against the database in TEST_DATABASE_DSN,
+// which must already be migrated. Its users table is truncated.
+func TestPostgresRepository(t *testing.T) {
+	pool := openTestPool(t)
+
+	porttest.RunUserRepositoryTests(t, func(t *testing.T) ports.UserRepository {
+		truncateUsers(t, pool)
+		return dbadapter.NewPostgresRepository(pool)
+	})
+}
+
+func TestPostgresUnitOfWork(t *testing.T) {
+	pool := openTestPool(t)
+
+	porttest.RunUnitOfWorkTests(t, func(t *testing.T) (ports.UserRepository, ports.UnitOfWork) {
+		truncateUsers(t, pool)
+		return dbadapter.NewPostgresRepository(pool), dbadapter.NewPostgresUnitOfWork(pool)
+	})
+}
+
+func openTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	pool, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func truncateUsers(t *testing.T, pool *pgxpool.Pool) {
+	t.Helper()
+	if _, err := pool.Exec(context.Background(), "TRUNCATE users"); err != nil {
+		t.Fatalf("failed to truncate users: %v", err)
+	}
+}
@@ -9,9 +9,12 @@ import (
 )
 
 type User struct {
-	ID        string           `json:"id"`
-	Email     string           `json:"email"`
-	Name      string           `json:"name"`
-	CreatedAt pgtype.Timestamp `json:"created_at"`
-	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	ID           string           `json:"id"`
+	Email        string           `json:"email"`
+	Name         string           `json:"name"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	Version      int64            `json:"version"`
+	DeletedAt    pgtype.Timestamp `json:"deleted_at"`
+	SearchVector interface{}      `json:"search_vector"`
 }
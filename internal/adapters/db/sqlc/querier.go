@@ -10,10 +10,17 @@ import (
 
 type Querier interface {
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteUser(ctx context.Context, id string) error
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id string) (User, error)
-	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	GetUserByIDForUpdate(ctx context.Context, id string) (User, error)
+	GetUserByIDIncludingDeleted(ctx context.Context, id string) (User, error)
+	PurgeUser(ctx context.Context, id string) (int64, error)
+	RestoreUser(ctx context.Context, id string) (User, error)
+	// Users match on their full-text vector or when name or email is similar to
+	// the query by at least pg_trgm.similarity_threshold (0.3 by default)
+	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error)
+	SoftDeleteUser(ctx context.Context, arg SoftDeleteUserParams) (int64, error)
+	TakenEmails(ctx context.Context, emails []string) ([]string, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 }
 
@@ -14,7 +14,7 @@ import (
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (id, email, name, created_at, updated_at)
 VALUES ($1, $2, $3, $4, $5)
-RETURNING id, email, name, created_at, updated_at
+RETURNING id, email, name, created_at, updated_at, version, deleted_at, search_vector
 `
 
 type CreateUserParams struct {
@@ -40,23 +40,16 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
-const deleteUser = `-- name: DeleteUser :exec
-DELETE FROM users
-WHERE id = $1
-`
-
-func (q *Queries) DeleteUser(ctx context.Context, id string) error {
-	_, err := q.db.Exec(ctx, deleteUser, id)
-	return err
-}
-
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, name, created_at, updated_at FROM users
-WHERE email = $1
+SELECT id, email, name, created_at, updated_at, version, deleted_at, search_vector FROM users
+WHERE email = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -68,13 +61,16 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, email, name, created_at, updated_at FROM users
-WHERE id = $1
+SELECT id, email, name, created_at, updated_at, version, deleted_at, search_vector FROM users
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUserByID(ctx context.Context, id string) (User, error) {
@@ -86,36 +82,137 @@ func (q *Queries) GetUserByID(ctx context.Context, id string) (User, error) {
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
-const listUsers = `-- name: ListUsers :many
-SELECT id, email, name, created_at, updated_at FROM users
-ORDER BY created_at DESC
-LIMIT $1 OFFSET $2
+const getUserByIDForUpdate = `-- name: GetUserByIDForUpdate :one
+SELECT id, email, name, created_at, updated_at, version, deleted_at, search_vector FROM users
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE
 `
 
-type ListUsersParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+func (q *Queries) GetUserByIDForUpdate(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByIDForUpdate, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+		&i.SearchVector,
+	)
+	return i, err
 }
 
-func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
-	rows, err := q.db.Query(ctx, listUsers, arg.Limit, arg.Offset)
+const getUserByIDIncludingDeleted = `-- name: GetUserByIDIncludingDeleted :one
+SELECT id, email, name, created_at, updated_at, version, deleted_at, search_vector FROM users
+WHERE id = $1
+`
+
+func (q *Queries) GetUserByIDIncludingDeleted(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByIDIncludingDeleted, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const purgeUser = `-- name: PurgeUser :execrows
+DELETE FROM users
+WHERE id = $1
+`
+
+func (q *Queries) PurgeUser(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL,
+    version = version + 1
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, email, name, created_at, updated_at, version, deleted_at, search_vector
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRow(ctx, restoreUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT users.id, users.email, users.name, users.created_at, users.updated_at, users.version, users.deleted_at, users.search_vector,
+       (ts_rank(search_vector, plainto_tsquery('simple', $1::text))
+        + greatest(similarity(name, $1::text), similarity(email, $1::text)))::float8 AS score
+FROM users
+WHERE deleted_at IS NULL
+  AND (search_vector @@ plainto_tsquery('simple', $1::text)
+    OR name % $1::text
+    OR email % $1::text)
+ORDER BY score DESC, id
+LIMIT $2
+`
+
+type SearchUsersParams struct {
+	Query string `json:"query"`
+	Limit int32  `json:"limit"`
+}
+
+type SearchUsersRow struct {
+	User  User    `json:"user"`
+	Score float64 `json:"score"`
+}
+
+// Users match on their full-text vector or when name or email is similar to
+// the query by at least pg_trgm.similarity_threshold (0.3 by default)
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error) {
+	rows, err := q.db.Query(ctx, searchUsers, arg.Query, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []User{}
+	items := []SearchUsersRow{}
 	for rows.Next() {
-		var i User
+		var i SearchUsersRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.Email,
-			&i.Name,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.User.ID,
+			&i.User.Email,
+			&i.User.Name,
+			&i.User.CreatedAt,
+			&i.User.UpdatedAt,
+			&i.User.Version,
+			&i.User.DeletedAt,
+			&i.User.SearchVector,
+			&i.Score,
 		); err != nil {
 			return nil, err
 		}
@@ -127,13 +224,59 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 	return items, nil
 }
 
+const softDeleteUser = `-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = $2,
+    version = version + 1
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type SoftDeleteUserParams struct {
+	ID        string           `json:"id"`
+	DeletedAt pgtype.Timestamp `json:"deleted_at"`
+}
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, arg SoftDeleteUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteUser, arg.ID, arg.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const takenEmails = `-- name: TakenEmails :many
+SELECT email FROM users
+WHERE email = ANY($1::text[])
+`
+
+func (q *Queries) TakenEmails(ctx context.Context, emails []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, takenEmails, emails)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		items = append(items, email)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET email = $2,
     name = $3,
-    updated_at = $4
-WHERE id = $1
-RETURNING id, email, name, created_at, updated_at
+    updated_at = $4,
+    version = version + 1
+WHERE id = $1 AND version = $5 AND deleted_at IS NULL
+RETURNING id, email, name, created_at, updated_at, version, deleted_at, search_vector
 `
 
 type UpdateUserParams struct {
@@ -141,6 +284,7 @@ type UpdateUserParams struct {
 	Email     string           `json:"email"`
 	Name      string           `json:"name"`
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	Version   int64            `json:"version"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
@@ -149,6 +293,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.Email,
 		arg.Name,
 		arg.UpdatedAt,
+		arg.Version,
 	)
 	var i User
 	err := row.Scan(
@@ -157,6 +302,9 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+		&i.SearchVector,
 	)
 	return i, err
 }
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+
+	sqlcdb "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/db/sqlc"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// txKey is the context key under which the current transaction is stored
+type txKey struct{}
+
+// PostgresUnitOfWork implements the UnitOfWork interface using pgxpool transactions
+type PostgresUnitOfWork struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresUnitOfWork creates a new PostgreSQL unit of work
+func NewPostgresUnitOfWork(db *pgxpool.Pool) ports.UnitOfWork {
+	return &PostgresUnitOfWork{
+		db: db,
+	}
+}
+
+// WithinTx runs fn inside a transaction, joining the one in ctx if present
+func (u *PostgresUnitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	// A serialization failure can surface at commit, after fn returned
+	err := pgx.BeginFunc(ctx, u.db, func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+	return translateError(err)
+}
+
+// dbFor returns the transaction in ctx, if any, or db
+func dbFor(ctx context.Context, db *pgxpool.Pool) sqlcdb.DBTX {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// queriesFor returns queries bound to the transaction in ctx, if any
+func queriesFor(ctx context.Context, queries *sqlcdb.Queries) *sqlcdb.Queries {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return queries.WithTx(tx)
+	}
+	return queries
+}
@@ -0,0 +1,21 @@
+package graphql
+
+import "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+
+// UserConnection is a page of users. It keeps the listing's filter so that
+// totalCount is only computed when a client asks for it.
+type UserConnection struct {
+	Edges    []*UserEdge `json:"edges"`
+	PageInfo *PageInfo   `json:"pageInfo"`
+
+	filter domain.UserFilter
+}
+
+// UserPage is a page of the offset-paginated listing. Like UserConnection it
+// keeps the listing's filter for totalCount.
+type UserPage struct {
+	Nodes       []*domain.User `json:"nodes"`
+	HasNextPage bool           `json:"hasNextPage"`
+
+	filter domain.UserFilter
+}
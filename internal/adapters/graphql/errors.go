@@ -0,0 +1,55 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/logger"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// categoryCodes maps domain error categories to the stable codes set in
+// the extensions of GraphQL errors
+var categoryCodes = map[domain.ErrorCategory]string{
+	domain.CategoryNotFound:        "NOT_FOUND",
+	domain.CategoryInvalid:         "BAD_USER_INPUT",
+	domain.CategoryConflict:        "CONFLICT",
+	domain.CategoryUnauthenticated: "UNAUTHENTICATED",
+	domain.CategoryForbidden:       "FORBIDDEN",
+	domain.CategoryInternal:        "INTERNAL_SERVER_ERROR",
+}
+
+// NewErrorPresenter returns a gqlgen error presenter that sets the code and
+// reason extensions of domain errors, and fieldViolations for invalid input.
+// Any other error a resolver returns is logged and reported as an internal
+// error without its message.
+func NewErrorPresenter(log *logger.Logger) graphql.ErrorPresenterFunc {
+	return func(ctx context.Context, err error) *gqlerror.Error {
+		gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+		domainErr, ok := domain.AsError(err)
+		if !ok {
+			// gqlgen wraps resolver errors in a gqlerror.Error too; only those
+			// without a cause, such as invalid arguments, are its own
+			var raised *gqlerror.Error
+			if errors.As(err, &raised) && raised.Err == nil {
+				return gqlErr
+			}
+			log.Errorf("GraphQL request failed at %s: %v", gqlErr.Path, err)
+			gqlErr.Message = domainErr.Message
+		}
+
+		if gqlErr.Extensions == nil {
+			gqlErr.Extensions = make(map[string]interface{})
+		}
+		gqlErr.Extensions["code"] = categoryCodes[domainErr.Category]
+		gqlErr.Extensions["reason"] = domainErr.Reason
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			gqlErr.Extensions["fieldViolations"] = validationErr.Violations
+		}
+		return gqlErr
+	}
+}
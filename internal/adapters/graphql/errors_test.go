@@ -0,0 +1,61 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/logger"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestErrorPresenter(t *testing.T) {
+	ctx := context.Background()
+	present := NewErrorPresenter(logger.New())
+
+	tests := []struct {
+		name        string
+		err         error
+		wantMessage string
+		wantCode    any
+	}{
+		{
+			name:        "domain error",
+			err:         fmt.Errorf("get user: %w", domain.ErrUserNotFound),
+			wantMessage: "get user: user not found",
+			wantCode:    "NOT_FOUND",
+		},
+		{
+			name:        "internal error",
+			err:         fmt.Errorf("find user: %w", errors.New("connection to 10.0.0.5 refused")),
+			wantMessage: domain.ErrInternalServer.Message,
+			wantCode:    "INTERNAL_SERVER_ERROR",
+		},
+		{
+			name:        "gqlgen error",
+			err:         gqlerror.Errorf("cannot parse %q as Int", "x"),
+			wantMessage: `cannot parse "x" as Int`,
+			wantCode:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Resolver errors reach the presenter wrapped as they are by
+			// graphql.AddError
+			got := present(ctx, graphql.ErrorOnPath(ctx, tt.err))
+			if got.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", got.Message, tt.wantMessage)
+			}
+			if code := got.Extensions["code"]; code != tt.wantCode {
+				t.Errorf("code extension = %v, want %v", code, tt.wantCode)
+			}
+			if strings.Contains(fmt.Sprint(got.Extensions), "10.0.0.5") {
+				t.Errorf("Extensions = %v, expose the internal error", got.Extensions)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package graphql
+
+import (
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+)
+
+// userSortFields maps the GraphQL order fields to the domain sort fields
+var userSortFields = map[UserOrderField]domain.UserSortField{
+	UserOrderFieldCreatedAt: domain.UserSortCreatedAt,
+	UserOrderFieldName:      domain.UserSortName,
+	UserOrderFieldEmail:     domain.UserSortEmail,
+}
+
+// toDomainFilter converts the listing arguments to a domain filter
+func toDomainFilter(includeDeleted *bool, input *UserFilterInput) (domain.UserFilter, error) {
+	filter := domain.UserFilter{}
+	if includeDeleted != nil {
+		filter.IncludeDeleted = *includeDeleted
+	}
+	if input == nil {
+		return filter, nil
+	}
+
+	if input.EmailPrefix != nil {
+		filter.EmailPrefix = *input.EmailPrefix
+	}
+	if input.NameContains != nil {
+		filter.NameContains = *input.NameContains
+	}
+
+	bounds := []struct {
+		name  string
+		value *string
+		dst   **time.Time
+	}{
+		{"createdAfter", input.CreatedAfter, &filter.CreatedAfter},
+		{"createdBefore", input.CreatedBefore, &filter.CreatedBefore},
+		{"updatedAfter", input.UpdatedAfter, &filter.UpdatedAfter},
+		{"updatedBefore", input.UpdatedBefore, &filter.UpdatedBefore},
+	}
+	for _, b := range bounds {
+		if b.value == nil {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, *b.value)
+		if err != nil {
+			return filter, &domain.ValidationError{
+				Violations: []domain.FieldViolation{{Field: b.name, Description: "must be an RFC 3339 time"}},
+			}
+		}
+		*b.dst = &t
+	}
+
+	return filter, nil
+}
+
+// toDomainSort converts an orderBy argument to a domain sort. A nil order
+// leaves the service default in place.
+func toDomainSort(order *UserOrder) domain.UserSort {
+	if order == nil {
+		return domain.UserSort{}
+	}
+	return domain.UserSort{
+		Field:      userSortFields[order.Field],
+		Descending: order.Direction == OrderDirectionDesc,
+	}
+}
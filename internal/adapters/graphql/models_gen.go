@@ -2,8 +2,165 @@
 
 package graphql
 
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+)
+
 type Mutation struct {
 }
 
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
+}
+
+// includeDeleted also returns soft-deleted users. There is no authorization layer, so any caller may set it.
 type Query struct {
 }
+
+type UserEdge struct {
+	// Pass as `after` to continue the listing after this user
+	Cursor string       `json:"cursor"`
+	Node   *domain.User `json:"node"`
+}
+
+// Narrows a user listing. Unset fields match every user; times are RFC 3339.
+type UserFilterInput struct {
+	// Matches emails starting with this prefix, ignoring case
+	EmailPrefix *string `json:"emailPrefix,omitempty"`
+	// Matches names containing this text, ignoring case
+	NameContains *string `json:"nameContains,omitempty"`
+	// Inclusive lower bound on createdAt
+	CreatedAfter *string `json:"createdAfter,omitempty"`
+	// Exclusive upper bound on createdAt
+	CreatedBefore *string `json:"createdBefore,omitempty"`
+	// Inclusive lower bound on updatedAt
+	UpdatedAfter *string `json:"updatedAfter,omitempty"`
+	// Exclusive upper bound on updatedAt
+	UpdatedBefore *string `json:"updatedBefore,omitempty"`
+}
+
+// Orders a user listing. Users with equal values are ordered by ID.
+type UserOrder struct {
+	Field     UserOrderField `json:"field"`
+	Direction OrderDirection `json:"direction"`
+}
+
+type OrderDirection string
+
+const (
+	OrderDirectionAsc  OrderDirection = "ASC"
+	OrderDirectionDesc OrderDirection = "DESC"
+)
+
+var AllOrderDirection = []OrderDirection{
+	OrderDirectionAsc,
+	OrderDirectionDesc,
+}
+
+func (e OrderDirection) IsValid() bool {
+	switch e {
+	case OrderDirectionAsc, OrderDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e OrderDirection) String() string {
+	return string(e)
+}
+
+func (e *OrderDirection) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderDirection", str)
+	}
+	return nil
+}
+
+func (e OrderDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *OrderDirection) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e OrderDirection) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type UserOrderField string
+
+const (
+	UserOrderFieldCreatedAt UserOrderField = "CREATED_AT"
+	UserOrderFieldName      UserOrderField = "NAME"
+	UserOrderFieldEmail     UserOrderField = "EMAIL"
+)
+
+var AllUserOrderField = []UserOrderField{
+	UserOrderFieldCreatedAt,
+	UserOrderFieldName,
+	UserOrderFieldEmail,
+}
+
+func (e UserOrderField) IsValid() bool {
+	switch e {
+	case UserOrderFieldCreatedAt, UserOrderFieldName, UserOrderFieldEmail:
+		return true
+	}
+	return false
+}
+
+func (e UserOrderField) String() string {
+	return string(e)
+}
+
+func (e *UserOrderField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = UserOrderField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid UserOrderField", str)
+	}
+	return nil
+}
+
+func (e UserOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *UserOrderField) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e UserOrderField) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -10,11 +10,24 @@ import (
 	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
 )
 
+// Error is the resolver for the error field.
+func (r *bulkCreateResultResolver) Error(ctx context.Context, obj *domain.BulkCreateResult) (*string, error) {
+	if obj.Error == "" {
+		return nil, nil
+	}
+	return &obj.Error, nil
+}
+
 // CreateUser is the resolver for the createUser field.
 func (r *mutationResolver) CreateUser(ctx context.Context, input domain.CreateUserInput) (*domain.User, error) {
 	return r.userService.CreateUser(ctx, &input)
 }
 
+// BulkCreateUsers is the resolver for the bulkCreateUsers field.
+func (r *mutationResolver) BulkCreateUsers(ctx context.Context, input []*domain.CreateUserInput) ([]*domain.BulkCreateResult, error) {
+	return r.userService.BulkCreateUsers(ctx, input)
+}
+
 // UpdateUser is the resolver for the updateUser field.
 func (r *mutationResolver) UpdateUser(ctx context.Context, id string, input domain.UpdateUserInput) (*domain.User, error) {
 	return r.userService.UpdateUser(ctx, id, &input)
@@ -29,13 +42,47 @@ func (r *mutationResolver) DeleteUser(ctx context.Context, id string) (bool, err
 	return true, nil
 }
 
+// RestoreUser is the resolver for the restoreUser field.
+func (r *mutationResolver) RestoreUser(ctx context.Context, id string) (*domain.User, error) {
+	return r.userService.RestoreUser(ctx, id)
+}
+
+// PurgeUser is the resolver for the purgeUser field.
+func (r *mutationResolver) PurgeUser(ctx context.Context, id string) (bool, error) {
+	err := r.userService.PurgeUser(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // User is the resolver for the user field.
-func (r *queryResolver) User(ctx context.Context, id string) (*domain.User, error) {
+func (r *queryResolver) User(ctx context.Context, id string, includeDeleted *bool) (*domain.User, error) {
+	if includeDeleted != nil && *includeDeleted {
+		return r.userService.GetUserIncludingDeleted(ctx, id)
+	}
 	return r.userService.GetUser(ctx, id)
 }
 
 // Users is the resolver for the users field.
-func (r *queryResolver) Users(ctx context.Context, limit *int, offset *int) ([]*domain.User, error) {
+func (r *queryResolver) Users(ctx context.Context, limit *int, offset *int, includeDeleted *bool, filter *UserFilterInput, orderBy *UserOrder) ([]*domain.User, error) {
+	l := 10
+	o := 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+	domainFilter, err := toDomainFilter(includeDeleted, filter)
+	if err != nil {
+		return nil, err
+	}
+	return r.userService.ListUsers(ctx, domainFilter, toDomainSort(orderBy), l, o)
+}
+
+// UsersPage is the resolver for the usersPage field.
+func (r *queryResolver) UsersPage(ctx context.Context, limit *int, offset *int, includeDeleted *bool, filter *UserFilterInput, orderBy *UserOrder) (*UserPage, error) {
 	l := 10
 	o := 0
 	if limit != nil {
@@ -44,7 +91,86 @@ func (r *queryResolver) Users(ctx context.Context, limit *int, offset *int) ([]*
 	if offset != nil {
 		o = *offset
 	}
-	return r.userService.ListUsers(ctx, l, o)
+	// Check the limit before asking for one more user
+	if err := domain.ValidateOffsetPage(l, o); err != nil {
+		return nil, err
+	}
+	domainFilter, err := toDomainFilter(includeDeleted, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra user to learn whether another page follows
+	users, err := r.userService.ListUsers(ctx, domainFilter, toDomainSort(orderBy), l+1, o)
+	if err != nil {
+		return nil, err
+	}
+	page := &UserPage{Nodes: users, filter: domainFilter}
+	if len(users) > l {
+		page.Nodes = users[:l]
+		page.HasNextPage = true
+	}
+	return page, nil
+}
+
+// UsersConnection is the resolver for the usersConnection field.
+func (r *queryResolver) UsersConnection(ctx context.Context, first *int, after *string, includeDeleted *bool, filter *UserFilterInput, orderBy *UserOrder) (*UserConnection, error) {
+	limit := 10
+	if first != nil {
+		limit = *first
+	}
+	cursor := ""
+	if after != nil {
+		cursor = *after
+	}
+	domainFilter, err := toDomainFilter(includeDeleted, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := r.userService.ListUsersPage(ctx, domainFilter, toDomainSort(orderBy), cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only forward pagination is supported, so hasPreviousPage is always
+	// false as the Relay spec allows
+	conn := &UserConnection{
+		Edges:    make([]*UserEdge, len(page.Users)),
+		PageInfo: &PageInfo{HasNextPage: page.HasNextPage()},
+		filter:   domainFilter,
+	}
+	for i, user := range page.Users {
+		conn.Edges[i] = &UserEdge{
+			Cursor: domain.NewUserCursor(user, page.Sort).Encode(),
+			Node:   user,
+		}
+	}
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.StartCursor = &conn.Edges[0].Cursor
+		conn.PageInfo.EndCursor = &conn.Edges[len(conn.Edges)-1].Cursor
+	}
+
+	return conn, nil
+}
+
+// UsersCount is the resolver for the usersCount field.
+func (r *queryResolver) UsersCount(ctx context.Context, includeDeleted *bool, filter *UserFilterInput, estimate *bool) (int, error) {
+	domainFilter, err := toDomainFilter(includeDeleted, filter)
+	if err != nil {
+		return 0, err
+	}
+	count, err := r.userService.CountUsers(ctx, domainFilter, estimate != nil && *estimate)
+	return int(count), err
+}
+
+// SearchUsers is the resolver for the searchUsers field.
+func (r *queryResolver) SearchUsers(ctx context.Context, query string, limit *int) ([]*domain.UserSearchResult, error) {
+	l := 10
+	if limit != nil {
+		l = *limit
+	}
+	return r.userService.SearchUsers(ctx, query, l)
 }
 
 // CreatedAt is the resolver for the createdAt field.
@@ -57,6 +183,30 @@ func (r *userResolver) UpdatedAt(ctx context.Context, obj *domain.User) (string,
 	return obj.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"), nil
 }
 
+// DeletedAt is the resolver for the deletedAt field.
+func (r *userResolver) DeletedAt(ctx context.Context, obj *domain.User) (*string, error) {
+	if obj.DeletedAt == nil {
+		return nil, nil
+	}
+	deletedAt := obj.DeletedAt.Format("2006-01-02T15:04:05Z07:00")
+	return &deletedAt, nil
+}
+
+// TotalCount is the resolver for the totalCount field.
+func (r *userConnectionResolver) TotalCount(ctx context.Context, obj *UserConnection, estimate *bool) (int, error) {
+	count, err := r.userService.CountUsers(ctx, obj.filter, estimate != nil && *estimate)
+	return int(count), err
+}
+
+// TotalCount is the resolver for the totalCount field.
+func (r *userPageResolver) TotalCount(ctx context.Context, obj *UserPage, estimate *bool) (int, error) {
+	count, err := r.userService.CountUsers(ctx, obj.filter, estimate != nil && *estimate)
+	return int(count), err
+}
+
+// BulkCreateResult returns BulkCreateResultResolver implementation.
+func (r *Resolver) BulkCreateResult() BulkCreateResultResolver { return &bulkCreateResultResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
@@ -66,6 +216,15 @@ func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 // User returns UserResolver implementation.
 func (r *Resolver) User() UserResolver { return &userResolver{r} }
 
+// UserConnection returns UserConnectionResolver implementation.
+func (r *Resolver) UserConnection() UserConnectionResolver { return &userConnectionResolver{r} }
+
+// UserPage returns UserPageResolver implementation.
+func (r *Resolver) UserPage() UserPageResolver { return &userPageResolver{r} }
+
+type bulkCreateResultResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
 type userResolver struct{ *Resolver }
+type userConnectionResolver struct{ *Resolver }
+type userPageResolver struct{ *Resolver }
@@ -0,0 +1,96 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/memory"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/services"
+)
+
+// newTestResolver returns a resolver over an in-memory service holding n
+// users
+func newTestResolver(t *testing.T, n int) *Resolver {
+	t.Helper()
+	service := services.NewUserService(memory.NewUserRepository(), memory.NewCacheRepository(100))
+	for i := range n {
+		input := &domain.CreateUserInput{Email: fmt.Sprintf("user%d@example.com", i), Name: fmt.Sprintf("User %d", i)}
+		if _, err := service.CreateUser(context.Background(), input); err != nil {
+			t.Fatalf("CreateUser() error = %v", err)
+		}
+	}
+	return NewResolver(service)
+}
+
+func TestUsersPage(t *testing.T) {
+	ctx := context.Background()
+	resolver := newTestResolver(t, 3)
+	query := resolver.Query()
+
+	tests := []struct {
+		limit, offset int
+		wantNodes     int
+		wantNext      bool
+	}{
+		{limit: 2, offset: 0, wantNodes: 2, wantNext: true},
+		{limit: 2, offset: 2, wantNodes: 1, wantNext: false},
+		{limit: 3, offset: 0, wantNodes: 3, wantNext: false},
+		{limit: 0, offset: 0, wantNodes: 0, wantNext: true},
+	}
+	for _, tt := range tests {
+		page, err := query.UsersPage(ctx, &tt.limit, &tt.offset, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("UsersPage(%d, %d) error = %v", tt.limit, tt.offset, err)
+		}
+		if len(page.Nodes) != tt.wantNodes || page.HasNextPage != tt.wantNext {
+			t.Errorf("UsersPage(%d, %d) = %d users, hasNextPage %t, want %d, %t",
+				tt.limit, tt.offset, len(page.Nodes), page.HasNextPage, tt.wantNodes, tt.wantNext)
+		}
+		if total, err := resolver.UserPage().TotalCount(ctx, page, nil); err != nil || total != 3 {
+			t.Errorf("TotalCount() = %d, %v, want 3", total, err)
+		}
+	}
+
+	prefix := "user1"
+	filter := &UserFilterInput{EmailPrefix: &prefix}
+	page, err := query.UsersPage(ctx, nil, nil, nil, filter, nil)
+	if err != nil {
+		t.Fatalf("UsersPage() error = %v", err)
+	}
+	if total, err := resolver.UserPage().TotalCount(ctx, page, nil); err != nil || total != 1 {
+		t.Errorf("TotalCount() of a filtered page = %d, %v, want 1", total, err)
+	}
+
+	negative := -1
+	var validationErr *domain.ValidationError
+	if _, err := query.UsersPage(ctx, &negative, nil, nil, nil, nil); !errors.As(err, &validationErr) {
+		t.Errorf("UsersPage() with a negative limit error = %v, want a validation error", err)
+	}
+	if _, err := query.UsersPage(ctx, nil, &negative, nil, nil, nil); !errors.As(err, &validationErr) {
+		t.Errorf("UsersPage() with a negative offset error = %v, want a validation error", err)
+	}
+}
+
+func TestUsersListsOffsetPage(t *testing.T) {
+	query := newTestResolver(t, 3).Query()
+	limit, offset := 2, 2
+	users, err := query.Users(context.Background(), &limit, &offset, nil, nil, nil)
+	if err != nil || len(users) != 1 {
+		t.Errorf("Users(%d, %d) = %d users, %v, want 1", limit, offset, len(users), err)
+	}
+}
+
+func TestBulkCreateUsersRejectsTooManyUsers(t *testing.T) {
+	input := make([]*domain.CreateUserInput, domain.MaxBulkCreateUsers+1)
+	for i := range input {
+		input[i] = &domain.CreateUserInput{Email: fmt.Sprintf("user%d@example.com", i), Name: "User"}
+	}
+	_, err := newTestResolver(t, 0).Mutation().BulkCreateUsers(context.Background(), input)
+	var validationErr *domain.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Violations[0].Field != "input" {
+		t.Errorf("BulkCreateUsers() of %d users error = %v, want a violation of input", len(input), err)
+	}
+}
@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorDomain is the ErrorInfo domain of the reasons this service returns
+const errorDomain = "user.UserService"
+
+// categoryCodes maps domain error categories to gRPC codes
+var categoryCodes = map[domain.ErrorCategory]codes.Code{
+	domain.CategoryNotFound:        codes.NotFound,
+	domain.CategoryInvalid:         codes.InvalidArgument,
+	domain.CategoryConflict:        codes.Aborted,
+	domain.CategoryUnauthenticated: codes.Unauthenticated,
+	domain.CategoryForbidden:       codes.PermissionDenied,
+	domain.CategoryInternal:        codes.Internal,
+}
+
+// toStatus converts an error from the user service to a gRPC status error
+func (s *UserServiceServer) toStatus(err error) error {
+	return statusFromError(s.log, err)
+}
+
+// statusFromError converts an error from the user service to a gRPC status
+// error with an ErrorInfo detail. Internal errors are logged and sent to the
+// client without their message.
+func statusFromError(log *logger.Logger, err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	domainErr, ok := domain.AsError(err)
+	message := err.Error()
+	if !ok {
+		log.Errorf("gRPC request failed: %v", err)
+		message = domainErr.Message
+	}
+
+	code := categoryCodes[domainErr.Category]
+	// A taken email is a conflict, but gRPC has a dedicated code for it
+	if domainErr == domain.ErrUserAlreadyExists {
+		code = codes.AlreadyExists
+	}
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason: domainErr.Reason,
+		Domain: errorDomain,
+	}}
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		details = append(details, toBadRequest(validationErr.Violations))
+	}
+	return withDetails(status.New(code, message), details...)
+}
+
+// toBadRequest converts field violations to a BadRequest detail. Domain
+// field names match the proto field names.
+func toBadRequest(violations []domain.FieldViolation) *errdetails.BadRequest {
+	badRequest := &errdetails.BadRequest{
+		FieldViolations: make([]*errdetails.BadRequest_FieldViolation, len(violations)),
+	}
+	for i, v := range violations {
+		badRequest.FieldViolations[i] = &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		}
+	}
+	return badRequest
+}
+
+// invalidArgument reports a malformed request field with BadRequest and
+// ErrorInfo details
+func invalidArgument(field, description string) error {
+	err := &domain.ValidationError{
+		Violations: []domain.FieldViolation{{Field: field, Description: description}},
+	}
+	return withDetails(status.New(codes.InvalidArgument, err.Error()),
+		toBadRequest(err.Violations),
+		&errdetails.ErrorInfo{
+			Reason: domain.ErrInvalidInput.Reason,
+			Domain: errorDomain,
+		},
+	)
+}
+
+// withDetails attaches details to st, falling back to st alone if they cannot
+// be encoded
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,123 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb "github.com/alexanderbkl/golang-hexagonal-boilerplate/api/grpc"
+	pbv2 "github.com/alexanderbkl/golang-hexagonal-boilerplate/api/grpc/v2"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/logger"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HealthCheck reports whether a dependency can serve requests
+type HealthCheck func(ctx context.Context) error
+
+// servedServices are the service names whose status depends on every
+// dependency: the server as a whole and each version of the user service
+var servedServices = []string{
+	"",
+	pb.UserService_ServiceDesc.ServiceName,
+	pbv2.UserService_ServiceDesc.ServiceName,
+}
+
+// HealthMonitor keeps a gRPC health server in line with the state of the
+// service's dependencies. Each dependency is reported under its own service
+// name, and the overall server ("") and the user services are SERVING only
+// while every dependency is healthy.
+type HealthMonitor struct {
+	server   *health.Server
+	log      *logger.Logger
+	interval time.Duration
+	timeout  time.Duration
+
+	mu      sync.Mutex
+	names   []string
+	checks  []HealthCheck
+	healthy map[string]bool
+}
+
+// NewHealthMonitor creates a monitor that runs its checks every interval,
+// giving each at most timeout
+func NewHealthMonitor(server *health.Server, interval, timeout time.Duration, log *logger.Logger) *HealthMonitor {
+	for _, service := range servedServices {
+		server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return &HealthMonitor{
+		server:   server,
+		log:      log,
+		interval: interval,
+		timeout:  timeout,
+		healthy:  make(map[string]bool),
+	}
+}
+
+// AddCheck registers a dependency check reported under name. Statuses start
+// as NOT_SERVING until the first round of checks.
+func (m *HealthMonitor) AddCheck(name string, check HealthCheck) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.names = append(m.names, name)
+	m.checks = append(m.checks, check)
+	m.server.SetServingStatus(name, healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+// Run checks the dependencies every interval until ctx is done
+func (m *HealthMonitor) Run(ctx context.Context) {
+	m.CheckNow(ctx)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.CheckNow(ctx)
+		}
+	}
+}
+
+// CheckNow runs every check once and updates the served statuses
+func (m *HealthMonitor) CheckNow(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	allHealthy := true
+	for i, name := range m.names {
+		checkCtx, cancel := context.WithTimeout(ctx, m.timeout)
+		err := m.checks[i](checkCtx)
+		cancel()
+
+		healthy := err == nil
+		if healthy != m.healthy[name] {
+			if healthy {
+				m.log.Infof("Health check %s passed", name)
+			} else {
+				m.log.Errorf("Health check %s failed: %v", name, err)
+			}
+		}
+		m.healthy[name] = healthy
+		m.server.SetServingStatus(name, servingStatus(healthy))
+		allHealthy = allHealthy && healthy
+	}
+
+	for _, service := range servedServices {
+		m.server.SetServingStatus(service, servingStatus(allHealthy))
+	}
+}
+
+// Shutdown reports every service as NOT_SERVING and ignores later checks,
+// so that load balancers stop routing to the server while it drains
+func (m *HealthMonitor) Shutdown() {
+	m.server.Shutdown()
+}
+
+func servingStatus(healthy bool) healthpb.HealthCheckResponse_ServingStatus {
+	if healthy {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/logger"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthMonitor(t *testing.T) {
+	server := health.NewServer()
+	monitor := NewHealthMonitor(server, time.Minute, time.Second, logger.New())
+
+	var redisErr error
+	monitor.AddCheck("postgres", func(ctx context.Context) error { return nil })
+	monitor.AddCheck("redis", func(ctx context.Context) error { return redisErr })
+
+	statusOf := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("Check(%q) error = %v", service, err)
+		}
+		return resp.Status
+	}
+	expect := func(service string, want healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		if got := statusOf(service); got != want {
+			t.Errorf("status of %q = %v, want %v", service, got, want)
+		}
+	}
+
+	expect("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	monitor.CheckNow(context.Background())
+	expect("", healthpb.HealthCheckResponse_SERVING)
+	expect("user.UserService", healthpb.HealthCheckResponse_SERVING)
+	expect("user.v2.UserService", healthpb.HealthCheckResponse_SERVING)
+	expect("redis", healthpb.HealthCheckResponse_SERVING)
+
+	redisErr = errors.New("connection refused")
+	monitor.CheckNow(context.Background())
+	expect("", healthpb.HealthCheckResponse_NOT_SERVING)
+	expect("user.UserService", healthpb.HealthCheckResponse_NOT_SERVING)
+	expect("postgres", healthpb.HealthCheckResponse_SERVING)
+	expect("redis", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	redisErr = nil
+	monitor.Shutdown()
+	monitor.CheckNow(context.Background())
+	expect("", healthpb.HealthCheckResponse_NOT_SERVING)
+	expect("postgres", healthpb.HealthCheckResponse_NOT_SERVING)
+}
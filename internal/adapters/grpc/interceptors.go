@@ -0,0 +1,205 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/google/uuid"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// maxRequestIDLength bounds the request IDs accepted from clients
+const maxRequestIDLength = 128
+
+// InterceptorConfig configures the interceptor chain
+type InterceptorConfig struct {
+	// Logger receives access logs and recovered panics
+	Logger *slog.Logger
+	// AccessLog logs every RPC with its status code and latency
+	AccessLog bool
+	// RequestIDHeader is the metadata key that carries request IDs
+	RequestIDHeader string
+}
+
+// ServerOptions returns the options installing the unary and stream
+// interceptor chains. Request IDs are assigned first so that access logs
+// carry them, and panics are recovered last so that access logs see the
+// resulting Internal status.
+func ServerOptions(cfg InterceptorConfig) []grpclib.ServerOption {
+	unary := []grpclib.UnaryServerInterceptor{RequestIDUnaryInterceptor(cfg.RequestIDHeader)}
+	stream := []grpclib.StreamServerInterceptor{RequestIDStreamInterceptor(cfg.RequestIDHeader)}
+	if cfg.AccessLog {
+		unary = append(unary, AccessLogUnaryInterceptor(cfg.Logger))
+		stream = append(stream, AccessLogStreamInterceptor(cfg.Logger))
+	}
+	unary = append(unary, RecoveryUnaryInterceptor(cfg.Logger))
+	stream = append(stream, RecoveryStreamInterceptor(cfg.Logger))
+
+	return []grpclib.ServerOption{
+		grpclib.ChainUnaryInterceptor(unary...),
+		grpclib.ChainStreamInterceptor(stream...),
+	}
+}
+
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the request ID assigned to the RPC of ctx
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestID returns the request ID sent by the client under header, or a new
+// one if it sent none or an unusable one
+func requestID(ctx context.Context, header string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(header); len(values) > 0 {
+		id := values[0]
+		if id != "" && len(id) <= maxRequestIDLength && isPrintableASCII(id) {
+			return id
+		}
+	}
+	return uuid.NewString()
+}
+
+func isPrintableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestIDUnaryInterceptor stores the request ID of each call in its context
+// and returns it to the client in the response header
+func RequestIDUnaryInterceptor(header string) grpclib.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (any, error) {
+		id := requestID(ctx, header)
+		// Failing to send the header must not fail the call
+		_ = grpclib.SetHeader(ctx, metadata.Pairs(header, id))
+		return handler(context.WithValue(ctx, requestIDKey{}, id), req)
+	}
+}
+
+// RequestIDStreamInterceptor stores the request ID of each stream in its
+// context and returns it to the client in the response header
+func RequestIDStreamInterceptor(header string) grpclib.StreamServerInterceptor {
+	return func(srv any, ss grpclib.ServerStream, info *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+		id := requestID(ss.Context(), header)
+		_ = ss.SetHeader(metadata.Pairs(header, id))
+		return handler(srv, &contextStream{
+			ServerStream: ss,
+			ctx:          context.WithValue(ss.Context(), requestIDKey{}, id),
+		})
+	}
+}
+
+// contextStream is a ServerStream with a replaced context
+type contextStream struct {
+	grpclib.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// AccessLogUnaryInterceptor logs every unary call once it completes
+func AccessLogUnaryInterceptor(log *slog.Logger) grpclib.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logAccess(ctx, log, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// AccessLogStreamInterceptor logs every stream once it completes
+func AccessLogStreamInterceptor(log *slog.Logger) grpclib.StreamServerInterceptor {
+	return func(srv any, ss grpclib.ServerStream, info *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logAccess(ss.Context(), log, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// logAccess writes the access log line of one RPC. Server-side failures are
+// logged at error level, client errors at warn level. Health checks are not
+// logged, as probes would drown out the other requests.
+func logAccess(ctx context.Context, log *slog.Logger, method string, start time.Time, err error) {
+	if strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") {
+		return
+	}
+	code := status.Code(err)
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("latency", time.Since(start)),
+		slog.String("request_id", RequestIDFromContext(ctx)),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		attrs = append(attrs, slog.String("peer", p.Addr.String()))
+	}
+
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.DeadlineExceeded:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+	log.LogAttrs(ctx, level, "grpc request", attrs...)
+}
+
+// RecoveryUnaryInterceptor turns a panic in a unary handler into an Internal
+// error instead of crashing the server
+func RecoveryUnaryInterceptor(log *slog.Logger) grpclib.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, log, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStreamInterceptor turns a panic in a stream handler into an
+// Internal error instead of crashing the server
+func RecoveryStreamInterceptor(log *slog.Logger) grpclib.StreamServerInterceptor {
+	return func(srv any, ss grpclib.ServerStream, info *grpclib.StreamServerInfo, handler grpclib.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ss.Context(), log, info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// recovered logs a recovered panic with its stack and returns the status sent
+// to the client, which does not reveal the panic value
+func recovered(ctx context.Context, log *slog.Logger, method string, r any) error {
+	log.ErrorContext(ctx, "grpc handler panicked",
+		slog.String("method", method),
+		slog.String("request_id", RequestIDFromContext(ctx)),
+		slog.Any("panic", r),
+		slog.String("stack", string(debug.Stack())),
+	)
+	return status.Error(codes.Internal, domain.ErrInternalServer.Message)
+}
@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testInfo = &grpclib.UnaryServerInfo{FullMethod: "/user.UserService/GetUser"}
+
+func TestRecoveryUnaryInterceptor(t *testing.T) {
+	interceptor := RecoveryUnaryInterceptor(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	_, err := interceptor(context.Background(), nil, testInfo, func(ctx context.Context, req any) (any, error) {
+		panic("boom")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("error = %v, want code Internal", err)
+	}
+	if status.Convert(err).Message() == "boom" {
+		t.Errorf("the panic value leaked to the client")
+	}
+}
+
+func TestRequestIDUnaryInterceptor(t *testing.T) {
+	interceptor := RequestIDUnaryInterceptor("x-request-id")
+	idOf := func(ctx context.Context) string {
+		var id string
+		interceptor(ctx, nil, testInfo, func(ctx context.Context, req any) (any, error) {
+			id = RequestIDFromContext(ctx)
+			return nil, nil
+		})
+		return id
+	}
+
+	incoming := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "abc-123"))
+	if got := idOf(incoming); got != "abc-123" {
+		t.Errorf("request ID = %q, want the client's %q", got, "abc-123")
+	}
+	if got := idOf(context.Background()); got == "" {
+		t.Errorf("request ID is empty, want a generated one")
+	}
+	invalid := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "bad\nid"))
+	if got := idOf(invalid); got == "bad\nid" {
+		t.Errorf("request ID %q with a control character was accepted", got)
+	}
+}
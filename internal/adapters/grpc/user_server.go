@@ -2,27 +2,65 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"io"
+	"time"
 
 	pb "github.com/alexanderbkl/golang-hexagonal-boilerplate/api/grpc"
 	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
 	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/logger"
 )
 
 // UserServiceServer implements the gRPC UserService server
 type UserServiceServer struct {
 	pb.UnimplementedUserServiceServer
 	userService ports.UserService
+	log         *logger.Logger
+	watchers    *watchers
 }
 
-// NewUserServiceServer creates a new gRPC user service server
-func NewUserServiceServer(userService ports.UserService) *UserServiceServer {
+// NewUserServiceServer creates a new gRPC user service server. Internal
+// errors are logged to log rather than returned to clients.
+func NewUserServiceServer(userService ports.UserService, log *logger.Logger) *UserServiceServer {
 	return &UserServiceServer{
 		userService: userService,
+		log:         log,
+		watchers:    newWatchers(),
 	}
 }
 
+// StopWatches ends the open WatchUsers streams with UNAVAILABLE. Call it
+// before stopping the gRPC server gracefully.
+func (s *UserServiceServer) StopWatches() {
+	s.watchers.stop()
+}
+
+// toProtoUser converts a domain user to its protobuf representation
+func toProtoUser(user *domain.User) *pb.User {
+	pbUser := &pb.User{
+		Id:        user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		CreatedAt: user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
+		Version:   user.Version,
+	}
+	if user.DeletedAt != nil {
+		pbUser.DeletedAt = user.DeletedAt.Format(time.RFC3339)
+	}
+	return pbUser
+}
+
+// toProtoUsers converts domain users to their protobuf representation
+func toProtoUsers(users []*domain.User) []*pb.User {
+	grpcUsers := make([]*pb.User, len(users))
+	for i, user := range users {
+		grpcUsers[i] = toProtoUser(user)
+	}
+	return grpcUsers
+}
+
 // CreateUser creates a new user
 func (s *UserServiceServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.UserResponse, error) {
 	user, err := s.userService.CreateUser(ctx, &domain.CreateUserInput{
@@ -30,67 +68,236 @@ func (s *UserServiceServer) CreateUser(ctx context.Context, req *pb.CreateUserRe
 		Name:  req.Name,
 	})
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, s.toStatus(err)
 	}
 
 	return &pb.UserResponse{
-		User: &pb.User{
-			Id:        user.ID,
-			Email:     user.Email,
-			Name:      user.Name,
-			CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		},
+		User: toProtoUser(user),
 	}, nil
 }
 
+// BulkCreateUsers creates the streamed users once the stream ends, in one
+// unit of work
+func (s *UserServiceServer) BulkCreateUsers(stream pb.UserService_BulkCreateUsersServer) error {
+	inputs, err := receiveInputs(stream.Recv, func(req *pb.CreateUserRequest) *domain.CreateUserInput {
+		return &domain.CreateUserInput{
+			Email: req.Email,
+			Name:  req.Name,
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	created, err := s.userService.ImportUsers(stream.Context(), inputs)
+	if err != nil {
+		return s.toStatus(err)
+	}
+	results := make([]*pb.BulkCreateResult, len(created))
+	for i, result := range created {
+		results[i] = toProtoBulkCreateResult(i, result)
+	}
+
+	return stream.SendAndClose(&pb.BulkCreateUsersResponse{
+		Results: results,
+	})
+}
+
+// receiveInputs reads a client stream to its end, converting each request
+// to the input of a user to create. It stops reading once the stream holds
+// more than domain.MaxImportUsers users, which ImportUsers then rejects.
+func receiveInputs[Req any](recv func() (Req, error), toInput func(Req) *domain.CreateUserInput) ([]*domain.CreateUserInput, error) {
+	var inputs []*domain.CreateUserInput
+	for len(inputs) <= domain.MaxImportUsers {
+		req, err := recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, toInput(req))
+	}
+	return inputs, nil
+}
+
+// bulkCreateStatuses maps domain bulk create outcomes to their protobuf enum
+var bulkCreateStatuses = map[domain.BulkCreateStatus]pb.BulkCreateStatus{
+	domain.BulkCreateCreated:   pb.BulkCreateStatus_BULK_CREATE_STATUS_CREATED,
+	domain.BulkCreateDuplicate: pb.BulkCreateStatus_BULK_CREATE_STATUS_DUPLICATE,
+	domain.BulkCreateInvalid:   pb.BulkCreateStatus_BULK_CREATE_STATUS_INVALID,
+}
+
+// toProtoBulkCreateResult converts the result of the user at index in the
+// stream to its protobuf representation
+func toProtoBulkCreateResult(index int, result *domain.BulkCreateResult) *pb.BulkCreateResult {
+	pbResult := &pb.BulkCreateResult{
+		Index:  int32(index),
+		Status: bulkCreateStatuses[result.Status],
+		Error:  result.Error,
+	}
+	if result.User != nil {
+		pbResult.User = toProtoUser(result.User)
+	}
+	return pbResult
+}
+
 // GetUser retrieves a user by ID
 func (s *UserServiceServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.UserResponse, error) {
-	user, err := s.userService.GetUser(ctx, req.Id)
+	var user *domain.User
+	var err error
+	if req.IncludeDeleted {
+		user, err = s.userService.GetUserIncludingDeleted(ctx, req.Id)
+	} else {
+		user, err = s.userService.GetUser(ctx, req.Id)
+	}
 	if err != nil {
-		if err == domain.ErrUserNotFound {
-			return nil, status.Error(codes.NotFound, err.Error())
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, s.toStatus(err)
 	}
 
 	return &pb.UserResponse{
-		User: &pb.User{
-			Id:        user.ID,
-			Email:     user.Email,
-			Name:      user.Name,
-			CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		},
+		User: toProtoUser(user),
 	}, nil
 }
 
-// ListUsers retrieves a list of users
+// ListUsers retrieves a page of users. Requests with an offset use the
+// legacy offset paging; all others are paged with page tokens.
 func (s *UserServiceServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
 	limit := int(req.Limit)
 	offset := int(req.Offset)
 	if limit == 0 {
 		limit = 10
 	}
+	filter, err := toDomainFilter(req)
+	if err != nil {
+		return nil, err
+	}
+	sort, err := domain.ParseUserSort(req.OrderBy)
+	if err != nil {
+		return nil, invalidArgument("order_by", `must be "created_at", "name" or "email", optionally followed by "desc"`)
+	}
 
-	users, err := s.userService.ListUsers(ctx, limit, offset)
+	resp := &pb.ListUsersResponse{}
+	if offset != 0 {
+		if req.PageToken != "" {
+			return nil, invalidArgument("page_token", "cannot be combined with offset")
+		}
+
+		// Fetch one extra user to learn whether another page follows
+		users, err := s.userService.ListUsers(ctx, filter, sort, limit+1, offset)
+		if err != nil {
+			return nil, s.toStatus(err)
+		}
+		if len(users) > limit {
+			users = users[:limit]
+			resp.HasNextPage = true
+		}
+		resp.Users = toProtoUsers(users)
+	} else {
+		page, err := s.userService.ListUsersPage(ctx, filter, sort, req.PageToken, limit)
+		if err != nil {
+			return nil, s.toStatus(err)
+		}
+		resp.Users = toProtoUsers(page.Users)
+		resp.NextPageToken = page.NextCursor
+		resp.HasNextPage = page.HasNextPage()
+	}
+
+	if req.TotalCountMode != pb.TotalCountMode_TOTAL_COUNT_MODE_UNSPECIFIED {
+		estimate := req.TotalCountMode == pb.TotalCountMode_TOTAL_COUNT_MODE_ESTIMATED
+		count, err := s.userService.CountUsers(ctx, filter, estimate)
+		if err != nil {
+			return nil, s.toStatus(err)
+		}
+		resp.TotalCount = &count
+	}
+
+	return resp, nil
+}
+
+// CountUsers counts the users matching the request's filters
+func (s *UserServiceServer) CountUsers(ctx context.Context, req *pb.CountUsersRequest) (*pb.CountUsersResponse, error) {
+	filter, err := toDomainFilter(req)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
-	grpcUsers := make([]*pb.User, len(users))
-	for i, user := range users {
-		grpcUsers[i] = &pb.User{
-			Id:        user.ID,
-			Email:     user.Email,
-			Name:      user.Name,
-			CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	count, err := s.userService.CountUsers(ctx, filter, req.Estimate)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	return &pb.CountUsersResponse{
+		Count: count,
+	}, nil
+}
+
+// userFilterRequest is implemented by the requests that carry user filters
+type userFilterRequest interface {
+	GetIncludeDeleted() bool
+	GetEmailPrefix() string
+	GetNameContains() string
+	GetCreatedAfter() string
+	GetCreatedBefore() string
+	GetUpdatedAfter() string
+	GetUpdatedBefore() string
+}
+
+// toDomainFilter converts the filters of a request to a domain filter. It
+// returns an InvalidArgument status error for malformed bounds.
+func toDomainFilter(req userFilterRequest) (domain.UserFilter, error) {
+	filter := domain.UserFilter{
+		IncludeDeleted: req.GetIncludeDeleted(),
+		EmailPrefix:    req.GetEmailPrefix(),
+		NameContains:   req.GetNameContains(),
+	}
+
+	bounds := []struct {
+		name  string
+		value string
+		dst   **time.Time
+	}{
+		{"created_after", req.GetCreatedAfter(), &filter.CreatedAfter},
+		{"created_before", req.GetCreatedBefore(), &filter.CreatedBefore},
+		{"updated_after", req.GetUpdatedAfter(), &filter.UpdatedAfter},
+		{"updated_before", req.GetUpdatedBefore(), &filter.UpdatedBefore},
+	}
+	for _, b := range bounds {
+		if b.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, b.value)
+		if err != nil {
+			return filter, invalidArgument(b.name, "must be an RFC 3339 time")
+		}
+		*b.dst = &t
+	}
+
+	return filter, nil
+}
+
+// SearchUsers finds users by name or email
+func (s *UserServiceServer) SearchUsers(ctx context.Context, req *pb.SearchUsersRequest) (*pb.SearchUsersResponse, error) {
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = 10
+	}
+
+	results, err := s.userService.SearchUsers(ctx, req.Query, limit)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	grpcResults := make([]*pb.UserSearchResult, len(results))
+	for i, result := range results {
+		grpcResults[i] = &pb.UserSearchResult{
+			User:  toProtoUser(result.User),
+			Score: result.Score,
 		}
 	}
 
-	return &pb.ListUsersResponse{
-		Users: grpcUsers,
+	return &pb.SearchUsersResponse{
+		Results: grpcResults,
 	}, nil
 }
 
@@ -103,37 +310,83 @@ func (s *UserServiceServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRe
 	if req.Name != nil {
 		input.Name = req.Name
 	}
+	if req.ExpectedVersion != nil {
+		input.ExpectedVersion = req.ExpectedVersion
+	}
 
 	user, err := s.userService.UpdateUser(ctx, req.Id, input)
 	if err != nil {
-		if err == domain.ErrUserNotFound {
-			return nil, status.Error(codes.NotFound, err.Error())
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, s.toStatus(err)
 	}
 
 	return &pb.UserResponse{
-		User: &pb.User{
-			Id:        user.ID,
-			Email:     user.Email,
-			Name:      user.Name,
-			CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		},
+		User: toProtoUser(user),
 	}, nil
 }
 
-// DeleteUser deletes a user
+// DeleteUser soft-deletes a user
 func (s *UserServiceServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
 	err := s.userService.DeleteUser(ctx, req.Id)
 	if err != nil {
-		if err == domain.ErrUserNotFound {
-			return nil, status.Error(codes.NotFound, err.Error())
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, s.toStatus(err)
 	}
 
 	return &pb.DeleteUserResponse{
 		Success: true,
 	}, nil
 }
+
+// RestoreUser undoes a soft delete
+func (s *UserServiceServer) RestoreUser(ctx context.Context, req *pb.RestoreUserRequest) (*pb.UserResponse, error) {
+	user, err := s.userService.RestoreUser(ctx, req.Id)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	return &pb.UserResponse{
+		User: toProtoUser(user),
+	}, nil
+}
+
+// PurgeUser permanently removes a user
+func (s *UserServiceServer) PurgeUser(ctx context.Context, req *pb.PurgeUserRequest) (*pb.PurgeUserResponse, error) {
+	err := s.userService.PurgeUser(ctx, req.Id)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	return &pb.PurgeUserResponse{
+		Success: true,
+	}, nil
+}
+
+// WatchUsers streams changes to users until the client cancels the stream
+func (s *UserServiceServer) WatchUsers(req *pb.WatchUsersRequest, stream pb.UserService_WatchUsersServer) error {
+	return s.watchers.watch(stream, s.userService, s.log, req.After, func(event *domain.UserEvent) error {
+		return stream.Send(toProtoUserEvent(event))
+	})
+}
+
+// userEventTypes maps domain user event types to their protobuf enum
+var userEventTypes = map[domain.UserEventType]pb.UserEventType{
+	domain.UserCreated:  pb.UserEventType_USER_EVENT_TYPE_CREATED,
+	domain.UserUpdated:  pb.UserEventType_USER_EVENT_TYPE_UPDATED,
+	domain.UserDeleted:  pb.UserEventType_USER_EVENT_TYPE_DELETED,
+	domain.UserRestored: pb.UserEventType_USER_EVENT_TYPE_RESTORED,
+	domain.UserPurged:   pb.UserEventType_USER_EVENT_TYPE_PURGED,
+}
+
+// toProtoUserEvent converts a domain user event to its protobuf
+// representation
+func toProtoUserEvent(event *domain.UserEvent) *pb.UserEvent {
+	pbEvent := &pb.UserEvent{
+		Id:         event.ID,
+		Type:       userEventTypes[event.Type],
+		UserId:     event.UserID,
+		OccurredAt: event.Time.Format(time.RFC3339Nano),
+	}
+	if event.User != nil {
+		pbEvent.User = toProtoUser(event.User)
+	}
+	return pbEvent
+}
@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"io"
+	"testing"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+)
+
+func TestReceiveInputs(t *testing.T) {
+	toInput := func(int) *domain.CreateUserInput { return &domain.CreateUserInput{} }
+
+	t.Run("ReadsToEnd", func(t *testing.T) {
+		sent := 0
+		recv := func() (int, error) {
+			if sent == 3 {
+				return 0, io.EOF
+			}
+			sent++
+			return sent, nil
+		}
+		inputs, err := receiveInputs(recv, toInput)
+		if err != nil || len(inputs) != 3 {
+			t.Errorf("receiveInputs() = %d inputs, %v, want 3", len(inputs), err)
+		}
+	})
+
+	t.Run("StopsPastLimit", func(t *testing.T) {
+		// The stream never ends
+		received := 0
+		recv := func() (int, error) {
+			received++
+			return received, nil
+		}
+		inputs, err := receiveInputs(recv, toInput)
+		if err != nil {
+			t.Fatalf("receiveInputs() error = %v", err)
+		}
+		if len(inputs) != domain.MaxImportUsers+1 || received != domain.MaxImportUsers+1 {
+			t.Errorf("receiveInputs() read %d requests and returned %d inputs, want %d",
+				received, len(inputs), domain.MaxImportUsers+1)
+		}
+	})
+
+	t.Run("StreamError", func(t *testing.T) {
+		recv := func() (int, error) { return 0, io.ErrUnexpectedEOF }
+		if _, err := receiveInputs(recv, toInput); err != io.ErrUnexpectedEOF {
+			t.Errorf("receiveInputs() error = %v, want %v", err, io.ErrUnexpectedEOF)
+		}
+	})
+}
@@ -0,0 +1,399 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pbv2 "github.com/alexanderbkl/golang-hexagonal-boilerplate/api/grpc/v2"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/logger"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// UserServiceV2Server implements the gRPC user.v2.UserService server
+type UserServiceV2Server struct {
+	pbv2.UnimplementedUserServiceServer
+	userService ports.UserService
+	log         *logger.Logger
+	watchers    *watchers
+}
+
+// NewUserServiceV2Server creates a new gRPC v2 user service server. Internal
+// errors are logged to log rather than returned to clients.
+func NewUserServiceV2Server(userService ports.UserService, log *logger.Logger) *UserServiceV2Server {
+	return &UserServiceV2Server{
+		userService: userService,
+		log:         log,
+		watchers:    newWatchers(),
+	}
+}
+
+// StopWatches ends the open WatchUsers streams with UNAVAILABLE. Call it
+// before stopping the gRPC server gracefully.
+func (s *UserServiceV2Server) StopWatches() {
+	s.watchers.stop()
+}
+
+// toStatus converts an error from the user service to a gRPC status error
+func (s *UserServiceV2Server) toStatus(err error) error {
+	return statusFromError(s.log, err)
+}
+
+// toProtoUserV2 converts a domain user to its v2 protobuf representation
+func toProtoUserV2(user *domain.User) *pbv2.User {
+	pbUser := &pbv2.User{
+		Id:         user.ID,
+		Email:      user.Email,
+		Name:       user.Name,
+		CreateTime: timestamppb.New(user.CreatedAt),
+		UpdateTime: timestamppb.New(user.UpdatedAt),
+		Version:    user.Version,
+	}
+	if user.DeletedAt != nil {
+		pbUser.DeleteTime = timestamppb.New(*user.DeletedAt)
+	}
+	return pbUser
+}
+
+// toProtoUsersV2 converts domain users to their v2 protobuf representation
+func toProtoUsersV2(users []*domain.User) []*pbv2.User {
+	pbUsers := make([]*pbv2.User, len(users))
+	for i, user := range users {
+		pbUsers[i] = toProtoUserV2(user)
+	}
+	return pbUsers
+}
+
+// prefixViolations qualifies the fields of a validation error with the
+// request field holding the user, so that they name proto field paths
+func prefixViolations(err error, prefix string) error {
+	var validationErr *domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+	violations := make([]domain.FieldViolation, len(validationErr.Violations))
+	for i, v := range validationErr.Violations {
+		violations[i] = domain.FieldViolation{Field: prefix + v.Field, Description: v.Description}
+	}
+	return &domain.ValidationError{Violations: violations}
+}
+
+// CreateUser creates a new user from the request's email and name
+func (s *UserServiceV2Server) CreateUser(ctx context.Context, req *pbv2.CreateUserRequest) (*pbv2.User, error) {
+	user, err := s.userService.CreateUser(ctx, &domain.CreateUserInput{
+		Email: req.GetUser().GetEmail(),
+		Name:  req.GetUser().GetName(),
+	})
+	if err != nil {
+		return nil, s.toStatus(prefixViolations(err, "user."))
+	}
+
+	return toProtoUserV2(user), nil
+}
+
+// BulkCreateUsers creates the streamed users once the stream ends, in one
+// unit of work
+func (s *UserServiceV2Server) BulkCreateUsers(stream pbv2.UserService_BulkCreateUsersServer) error {
+	inputs, err := receiveInputs(stream.Recv, func(req *pbv2.CreateUserRequest) *domain.CreateUserInput {
+		return &domain.CreateUserInput{
+			Email: req.GetUser().GetEmail(),
+			Name:  req.GetUser().GetName(),
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	created, err := s.userService.ImportUsers(stream.Context(), inputs)
+	if err != nil {
+		return s.toStatus(err)
+	}
+	results := make([]*pbv2.BulkCreateResult, len(created))
+	for i, result := range created {
+		results[i] = toProtoBulkCreateResultV2(i, result)
+	}
+
+	return stream.SendAndClose(&pbv2.BulkCreateUsersResponse{
+		Results: results,
+	})
+}
+
+// bulkCreateStatusesV2 maps domain bulk create outcomes to their v2
+// protobuf enum
+var bulkCreateStatusesV2 = map[domain.BulkCreateStatus]pbv2.BulkCreateStatus{
+	domain.BulkCreateCreated:   pbv2.BulkCreateStatus_BULK_CREATE_STATUS_CREATED,
+	domain.BulkCreateDuplicate: pbv2.BulkCreateStatus_BULK_CREATE_STATUS_DUPLICATE,
+	domain.BulkCreateInvalid:   pbv2.BulkCreateStatus_BULK_CREATE_STATUS_INVALID,
+}
+
+// toProtoBulkCreateResultV2 converts the result of the user at index in the
+// stream to its v2 protobuf representation
+func toProtoBulkCreateResultV2(index int, result *domain.BulkCreateResult) *pbv2.BulkCreateResult {
+	pbResult := &pbv2.BulkCreateResult{
+		Index:  int32(index),
+		Status: bulkCreateStatusesV2[result.Status],
+		Error:  result.Error,
+	}
+	if result.User != nil {
+		pbResult.User = toProtoUserV2(result.User)
+	}
+	return pbResult
+}
+
+// GetUser retrieves a user by ID
+func (s *UserServiceV2Server) GetUser(ctx context.Context, req *pbv2.GetUserRequest) (*pbv2.User, error) {
+	var user *domain.User
+	var err error
+	if req.IncludeDeleted {
+		user, err = s.userService.GetUserIncludingDeleted(ctx, req.Id)
+	} else {
+		user, err = s.userService.GetUser(ctx, req.Id)
+	}
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	return toProtoUserV2(user), nil
+}
+
+// ListUsers retrieves a page of users
+func (s *UserServiceV2Server) ListUsers(ctx context.Context, req *pbv2.ListUsersRequest) (*pbv2.ListUsersResponse, error) {
+	pageSize := int(req.PageSize)
+	if pageSize == 0 {
+		pageSize = 10
+	}
+	filter, err := toDomainFilterV2(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	sort, err := parseUserSortV2(req.OrderBy)
+	if err != nil {
+		return nil, invalidArgument("order_by", `must be "create_time", "name" or "email", optionally followed by "desc"`)
+	}
+
+	page, err := s.userService.ListUsersPage(ctx, filter, sort, req.PageToken, pageSize)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	resp := &pbv2.ListUsersResponse{
+		Users:         toProtoUsersV2(page.Users),
+		NextPageToken: page.NextCursor,
+	}
+
+	if req.TotalCountMode != pbv2.TotalCountMode_TOTAL_COUNT_MODE_UNSPECIFIED {
+		estimate := req.TotalCountMode == pbv2.TotalCountMode_TOTAL_COUNT_MODE_ESTIMATED
+		count, err := s.userService.CountUsers(ctx, filter, estimate)
+		if err != nil {
+			return nil, s.toStatus(err)
+		}
+		resp.TotalCount = &count
+	}
+
+	return resp, nil
+}
+
+// parseUserSortV2 parses an order_by clause, in which the creation time is
+// named create_time
+func parseUserSortV2(orderBy string) (domain.UserSort, error) {
+	parts := strings.Fields(orderBy)
+	if len(parts) > 0 && parts[0] == "create_time" {
+		parts[0] = string(domain.UserSortCreatedAt)
+	}
+	return domain.ParseUserSort(strings.Join(parts, " "))
+}
+
+// CountUsers counts the users matching the request's filter
+func (s *UserServiceV2Server) CountUsers(ctx context.Context, req *pbv2.CountUsersRequest) (*pbv2.CountUsersResponse, error) {
+	filter, err := toDomainFilterV2(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.userService.CountUsers(ctx, filter, req.Estimate)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	return &pbv2.CountUsersResponse{
+		Count: count,
+	}, nil
+}
+
+// toDomainFilterV2 converts a v2 filter, which may be nil, to a domain
+// filter. It returns an InvalidArgument status error for malformed bounds.
+func toDomainFilterV2(f *pbv2.UserFilter) (domain.UserFilter, error) {
+	filter := domain.UserFilter{
+		IncludeDeleted: f.GetIncludeDeleted(),
+		EmailPrefix:    f.GetEmailPrefix(),
+		NameContains:   f.GetNameContains(),
+	}
+
+	bounds := []struct {
+		name  string
+		value *timestamppb.Timestamp
+		dst   **time.Time
+	}{
+		{"filter.create_time_after", f.GetCreateTimeAfter(), &filter.CreatedAfter},
+		{"filter.create_time_before", f.GetCreateTimeBefore(), &filter.CreatedBefore},
+		{"filter.update_time_after", f.GetUpdateTimeAfter(), &filter.UpdatedAfter},
+		{"filter.update_time_before", f.GetUpdateTimeBefore(), &filter.UpdatedBefore},
+	}
+	for _, b := range bounds {
+		if b.value == nil {
+			continue
+		}
+		if err := b.value.CheckValid(); err != nil {
+			return filter, invalidArgument(b.name, "must be a valid timestamp")
+		}
+		t := b.value.AsTime()
+		*b.dst = &t
+	}
+
+	return filter, nil
+}
+
+// SearchUsers finds users by name or email
+func (s *UserServiceV2Server) SearchUsers(ctx context.Context, req *pbv2.SearchUsersRequest) (*pbv2.SearchUsersResponse, error) {
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = 10
+	}
+
+	results, err := s.userService.SearchUsers(ctx, req.Query, limit)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	pbResults := make([]*pbv2.UserSearchResult, len(results))
+	for i, result := range results {
+		pbResults[i] = &pbv2.UserSearchResult{
+			User:  toProtoUserV2(result.User),
+			Score: result.Score,
+		}
+	}
+
+	return &pbv2.SearchUsersResponse{
+		Results: pbResults,
+	}, nil
+}
+
+// UpdateUser updates the fields of a user named by the update mask
+func (s *UserServiceV2Server) UpdateUser(ctx context.Context, req *pbv2.UpdateUserRequest) (*pbv2.User, error) {
+	input, err := toUpdateUserInput(req)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userService.UpdateUser(ctx, req.User.Id, input)
+	if err != nil {
+		return nil, s.toStatus(prefixViolations(err, "user."))
+	}
+
+	return toProtoUserV2(user), nil
+}
+
+// toUpdateUserInput converts an update request to a domain input following
+// AIP-134: without an update mask, every non-empty field is updated, and "*"
+// updates every mutable field. A non-zero user version is the expected
+// version.
+func toUpdateUserInput(req *pbv2.UpdateUserRequest) (*domain.UpdateUserInput, error) {
+	user := req.GetUser()
+	if user == nil {
+		return nil, invalidArgument("user", "is required")
+	}
+	if user.Id == "" {
+		return nil, invalidArgument("user.id", "is required")
+	}
+
+	input := &domain.UpdateUserInput{}
+	if req.UpdateMask == nil {
+		if user.Email != "" {
+			input.Email = &user.Email
+		}
+		if user.Name != "" {
+			input.Name = &user.Name
+		}
+	}
+	for _, path := range req.UpdateMask.GetPaths() {
+		switch path {
+		case "email":
+			input.Email = &user.Email
+		case "name":
+			input.Name = &user.Name
+		case "*":
+			input.Email = &user.Email
+			input.Name = &user.Name
+		default:
+			return nil, invalidArgument("update_mask", fmt.Sprintf("%q is not a field that can be updated", path))
+		}
+	}
+	if user.Version != 0 {
+		input.ExpectedVersion = &user.Version
+	}
+
+	return input, nil
+}
+
+// DeleteUser soft-deletes a user
+func (s *UserServiceV2Server) DeleteUser(ctx context.Context, req *pbv2.DeleteUserRequest) (*emptypb.Empty, error) {
+	if err := s.userService.DeleteUser(ctx, req.Id); err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// RestoreUser undoes a soft delete
+func (s *UserServiceV2Server) RestoreUser(ctx context.Context, req *pbv2.RestoreUserRequest) (*pbv2.User, error) {
+	user, err := s.userService.RestoreUser(ctx, req.Id)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	return toProtoUserV2(user), nil
+}
+
+// PurgeUser permanently removes a user
+func (s *UserServiceV2Server) PurgeUser(ctx context.Context, req *pbv2.PurgeUserRequest) (*emptypb.Empty, error) {
+	if err := s.userService.PurgeUser(ctx, req.Id); err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// WatchUsers streams changes to users until the client cancels the stream
+func (s *UserServiceV2Server) WatchUsers(req *pbv2.WatchUsersRequest, stream pbv2.UserService_WatchUsersServer) error {
+	return s.watchers.watch(stream, s.userService, s.log, req.After, func(event *domain.UserEvent) error {
+		return stream.Send(toProtoUserEventV2(event))
+	})
+}
+
+// userEventTypesV2 maps domain user event types to their v2 protobuf enum
+var userEventTypesV2 = map[domain.UserEventType]pbv2.UserEventType{
+	domain.UserCreated:  pbv2.UserEventType_USER_EVENT_TYPE_CREATED,
+	domain.UserUpdated:  pbv2.UserEventType_USER_EVENT_TYPE_UPDATED,
+	domain.UserDeleted:  pbv2.UserEventType_USER_EVENT_TYPE_DELETED,
+	domain.UserRestored: pbv2.UserEventType_USER_EVENT_TYPE_RESTORED,
+	domain.UserPurged:   pbv2.UserEventType_USER_EVENT_TYPE_PURGED,
+}
+
+// toProtoUserEventV2 converts a domain user event to its v2 protobuf
+// representation
+func toProtoUserEventV2(event *domain.UserEvent) *pbv2.UserEvent {
+	pbEvent := &pbv2.UserEvent{
+		Id:        event.ID,
+		Type:      userEventTypesV2[event.Type],
+		UserId:    event.UserID,
+		EventTime: timestamppb.New(event.Time),
+	}
+	if event.User != nil {
+		pbEvent.User = toProtoUserV2(event.User)
+	}
+	return pbEvent
+}
@@ -0,0 +1,57 @@
+package grpc
+
+import (
+	"testing"
+
+	pbv2 "github.com/alexanderbkl/golang-hexagonal-boilerplate/api/grpc/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestToUpdateUserInput(t *testing.T) {
+	user := &pbv2.User{Id: "u1", Email: "ann@example.com", Name: ""}
+	tests := []struct {
+		name      string
+		mask      *fieldmaskpb.FieldMask
+		wantEmail bool
+		wantName  bool
+	}{
+		{"NoMaskUpdatesNonEmptyFields", nil, true, false},
+		{"MaskedField", &fieldmaskpb.FieldMask{Paths: []string{"name"}}, false, true},
+		{"Wildcard", &fieldmaskpb.FieldMask{Paths: []string{"*"}}, true, true},
+		{"EmptyMaskUpdatesNothing", &fieldmaskpb.FieldMask{}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := toUpdateUserInput(&pbv2.UpdateUserRequest{User: user, UpdateMask: tt.mask})
+			if err != nil {
+				t.Fatalf("toUpdateUserInput() error = %v", err)
+			}
+			if (input.Email != nil) != tt.wantEmail || (input.Name != nil) != tt.wantName {
+				t.Errorf("toUpdateUserInput() updates email %t and name %t, want %t and %t",
+					input.Email != nil, input.Name != nil, tt.wantEmail, tt.wantName)
+			}
+			if input.ExpectedVersion != nil {
+				t.Errorf("toUpdateUserInput() expected version = %d, want none", *input.ExpectedVersion)
+			}
+		})
+	}
+
+	t.Run("Version", func(t *testing.T) {
+		input, err := toUpdateUserInput(&pbv2.UpdateUserRequest{User: &pbv2.User{Id: "u1", Version: 3}})
+		if err != nil || input.ExpectedVersion == nil || *input.ExpectedVersion != 3 {
+			t.Errorf("toUpdateUserInput() = %v, %v, want expected version 3", input, err)
+		}
+	})
+
+	for _, path := range []string{"id", "create_time", "nickname"} {
+		_, err := toUpdateUserInput(&pbv2.UpdateUserRequest{
+			User:       user,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{path}},
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("toUpdateUserInput() with mask path %q error = %v, want InvalidArgument", path, err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/logger"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// watchers ends the WatchUsers streams of a server when it shuts down, as
+// they would otherwise keep GracefulStop waiting until its deadline
+type watchers struct {
+	once     sync.Once
+	stopping chan struct{}
+}
+
+func newWatchers() *watchers {
+	return &watchers{stopping: make(chan struct{})}
+}
+
+// stop ends every open stream with UNAVAILABLE, so that clients reconnect to
+// another instance
+func (w *watchers) stop() {
+	w.once.Do(func() { close(w.stopping) })
+}
+
+// watch subscribes to user events after the cursor after and passes each to
+// send until the client cancels, send fails or the watchers are stopped
+func (w *watchers) watch(stream grpclib.ServerStream, userService ports.UserService, log *logger.Logger, after string, send func(*domain.UserEvent) error) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-w.stopping:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	events, err := userService.WatchUsers(ctx, after)
+	if err != nil {
+		return statusFromError(log, err)
+	}
+	defer events.Close()
+
+	// Send the headers now, so that clients know when they are subscribed
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for {
+		event, err := events.Next(ctx)
+		if err != nil {
+			select {
+			case <-w.stopping:
+				return status.Error(codes.Unavailable, "server is shutting down")
+			default:
+			}
+			return statusFromError(log, err)
+		}
+		if err := send(event); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+// DefaultCacheCapacity is the number of entries kept when no capacity is given
+const DefaultCacheCapacity = 10000
+
+// cacheEntry is a single value stored in the LRU list
+type cacheEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// expired reports whether the entry has outlived its expiration
+func (e *cacheEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// CacheRepository implements the CacheRepository interface with a bounded,
+// in-process LRU
+type CacheRepository struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+// NewCacheRepository creates a new in-memory cache holding at most capacity
+// entries, or DefaultCacheCapacity if capacity is not positive
+func NewCacheRepository(capacity int) ports.CacheRepository {
+	if capacity <= 0 {
+		capacity = DefaultCacheCapacity
+	}
+	return &CacheRepository{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Set stores a value in the cache. A zero expiration keeps the value until it
+// is evicted.
+func (c *CacheRepository) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(key, value, expiration)
+	return nil
+}
+
+// Get retrieves a value from the cache
+func (c *CacheRepository) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := c.get(key)
+	if !ok {
+		return nil, ports.ErrCacheMiss
+	}
+	return value, nil
+}
+
+// Delete removes a value from the cache
+func (c *CacheRepository) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+	return nil
+}
+
+// Exists checks if a key exists in the cache
+func (c *CacheRepository) Exists(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return false, nil
+	}
+	if elem.Value.(*cacheEntry).expired(time.Now()) {
+		c.removeElement(elem)
+		return false, nil
+	}
+	return true, nil
+}
+
+// GetMany retrieves several values from the cache
+func (c *CacheRepository) GetMany(ctx context.Context, keys []string) (map[string][]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if value, ok := c.get(key); ok {
+			result[key] = value
+		}
+	}
+	return result, nil
+}
+
+// SetMany stores several values in the cache
+func (c *CacheRepository) SetMany(ctx context.Context, values map[string][]byte, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, value := range values {
+		c.set(key, value, expiration)
+	}
+	return nil
+}
+
+// get returns a copy of a live entry and marks it as recently used. The
+// caller must hold mu.
+func (c *CacheRepository) get(key string) ([]byte, bool) {
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if entry.expired(time.Now()) {
+		c.removeElement(elem)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return append([]byte(nil), entry.value...), true
+}
+
+// set stores a copy of value and evicts entries beyond capacity. The caller
+// must hold mu.
+func (c *CacheRepository) set(key string, value []byte, expiration time.Duration) {
+	var expiresAt time.Time
+	if expiration > 0 {
+		expiresAt = time.Now().Add(expiration)
+	}
+	value = append([]byte(nil), value...)
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&cacheEntry{
+		key:       key,
+		value:     value,
+		expiresAt: expiresAt,
+	})
+
+	// Evict the least recently used entries beyond capacity. Expired entries
+	// are otherwise removed lazily when they are next accessed.
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+	}
+}
+
+func (c *CacheRepository) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*cacheEntry).key)
+}
@@ -0,0 +1,95 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/memory"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports/porttest"
+)
+
+func TestCacheRepository(t *testing.T) {
+	porttest.RunCacheRepositoryTests(t, func(t *testing.T) ports.CacheRepository {
+		return memory.NewCacheRepository(100)
+	})
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewCacheRepository(3)
+	for _, key := range []string{"a", "b", "c"} {
+		mustSet(t, cache, key)
+	}
+
+	// Reading a makes b the least recently used entry
+	if _, err := cache.Get(ctx, "a"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	mustSet(t, cache, "d")
+	assertKeys(t, cache, map[string]bool{"a": true, "b": false, "c": true, "d": true})
+
+	// Overwriting c makes it recently used, leaving a to be evicted next
+	mustSet(t, cache, "c")
+	mustSet(t, cache, "e")
+	assertKeys(t, cache, map[string]bool{"a": false, "c": true, "d": true, "e": true})
+}
+
+func TestCacheDefaultsCapacity(t *testing.T) {
+	for _, capacity := range []int{0, -1} {
+		t.Run(fmt.Sprint(capacity), func(t *testing.T) {
+			cache := memory.NewCacheRepository(capacity)
+			for i := 0; i <= memory.DefaultCacheCapacity; i++ {
+				mustSet(t, cache, fmt.Sprint(i))
+			}
+
+			// Only the oldest entry is evicted
+			last := fmt.Sprint(memory.DefaultCacheCapacity)
+			assertKeys(t, cache, map[string]bool{"0": false, "1": true, last: true})
+		})
+	}
+}
+
+func TestCacheExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewCacheRepository(10)
+	if err := cache.Set(ctx, "short", []byte("1"), 20*time.Millisecond); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := cache.SetMany(ctx, map[string][]byte{"batch": []byte("1")}, 20*time.Millisecond); err != nil {
+		t.Fatalf("SetMany() error = %v", err)
+	}
+	if err := cache.Set(ctx, "forever", []byte("1"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	assertKeys(t, cache, map[string]bool{"short": true, "batch": true, "forever": true})
+
+	time.Sleep(40 * time.Millisecond)
+	if _, err := cache.Get(ctx, "short"); !errors.Is(err, ports.ErrCacheMiss) {
+		t.Errorf("Get() of an expired key error = %v, want %v", err, ports.ErrCacheMiss)
+	}
+	if got, _ := cache.GetMany(ctx, []string{"batch", "forever"}); len(got) != 1 || got["forever"] == nil {
+		t.Errorf("GetMany() after expiry = %q, want only forever", got)
+	}
+	assertKeys(t, cache, map[string]bool{"short": false, "batch": false, "forever": true})
+}
+
+func mustSet(t *testing.T, cache ports.CacheRepository, key string) {
+	t.Helper()
+	if err := cache.Set(context.Background(), key, []byte(key), 0); err != nil {
+		t.Fatalf("Set(%q) error = %v", key, err)
+	}
+}
+
+// assertKeys checks which keys are present without touching their recency
+func assertKeys(t *testing.T, cache ports.CacheRepository, want map[string]bool) {
+	t.Helper()
+	for key, present := range want {
+		if got, err := cache.Exists(context.Background(), key); err != nil || got != present {
+			t.Errorf("Exists(%q) = %t, %v, want %t", key, got, err, present)
+		}
+	}
+}
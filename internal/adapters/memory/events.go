@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+// DefaultEventHistory is the number of events retained when no size is given
+const DefaultEventHistory = 10000
+
+// UserEventBus implements the UserEventBus interface within one process.
+// Event IDs are sequence numbers starting at 1.
+type UserEventBus struct {
+	mu      sync.Mutex
+	history int
+	// events holds the retained events, oldest first
+	events []*domain.UserEvent
+	// next is the sequence number of the next event published
+	next uint64
+	// published is closed and replaced whenever events are published
+	published chan struct{}
+}
+
+// NewUserEventBus creates an in-process event bus retaining the last
+// history events
+func NewUserEventBus(history int) ports.UserEventBus {
+	if history <= 0 {
+		history = DefaultEventHistory
+	}
+	return &UserEventBus{
+		history:   history,
+		next:      1,
+		published: make(chan struct{}),
+	}
+}
+
+// Publish numbers the events, retains copies of them and wakes up waiting
+// streams. The oldest events are dropped beyond history events.
+func (b *UserEventBus) Publish(ctx context.Context, events ...*domain.UserEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, event := range events {
+		event.ID = strconv.FormatUint(b.next, 10)
+		b.next++
+		stored := *event
+		if event.User != nil {
+			user := *event.User
+			stored.User = &user
+		}
+		b.events = append(b.events, &stored)
+	}
+	if len(b.events) > b.history {
+		b.events = append([]*domain.UserEvent(nil), b.events[len(b.events)-b.history:]...)
+	}
+	close(b.published)
+	b.published = make(chan struct{})
+	return nil
+}
+
+// Subscribe returns a stream of the events following the one with sequence
+// number after, or of the events published from now on if after is empty
+func (b *UserEventBus) Subscribe(ctx context.Context, after string) (ports.UserEventStream, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if after == "" {
+		return &userEventStream{bus: b, next: b.next}, nil
+	}
+	seq, err := strconv.ParseUint(after, 10, 64)
+	if err != nil || seq >= b.next {
+		return nil, domain.ErrInvalidCursor
+	}
+	if seq+1 < b.oldest() {
+		return nil, domain.ErrCursorExpired
+	}
+	return &userEventStream{bus: b, next: seq + 1}, nil
+}
+
+// oldest returns the sequence number of the oldest retained event. The
+// caller must hold the lock.
+func (b *UserEventBus) oldest() uint64 {
+	return b.next - uint64(len(b.events))
+}
+
+// userEventStream follows a UserEventBus from a sequence number
+type userEventStream struct {
+	bus  *UserEventBus
+	next uint64
+}
+
+// Next returns a copy of the next event, waiting for it to be published. It
+// returns domain.ErrCursorExpired once the event has been dropped.
+func (s *userEventStream) Next(ctx context.Context) (*domain.UserEvent, error) {
+	for {
+		s.bus.mu.Lock()
+		if s.next < s.bus.oldest() {
+			s.bus.mu.Unlock()
+			return nil, domain.ErrCursorExpired
+		}
+		if s.next < s.bus.next {
+			stored := *s.bus.events[s.next-s.bus.oldest()]
+			s.next++
+			s.bus.mu.Unlock()
+			return &stored, nil
+		}
+		published := s.bus.published
+		s.bus.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-published:
+		}
+	}
+}
+
+// Close does nothing, as a stream holds no resources
+func (s *userEventStream) Close() error {
+	return nil
+}
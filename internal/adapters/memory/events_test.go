@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/memory"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports/porttest"
+)
+
+func TestUserEventBus(t *testing.T) {
+	porttest.RunUserEventBusTests(t, func(t *testing.T, history int) ports.UserEventBus {
+		return memory.NewUserEventBus(history)
+	})
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+// txKey is the context key of the unit of work a call runs in
+type txKey struct{}
+
+// participant is implemented by in-memory stores that can take part in a
+// unit of work
+type participant interface {
+	// txLocker returns the lock a unit of work holds while it runs
+	txLocker() sync.Locker
+}
+
+// tx is a unit of work in progress
+type tx struct {
+	// held is the set of stores whose lock the unit of work holds
+	held map[participant]bool
+	// undo holds the functions reverting the writes made so far, in order
+	undo []func()
+}
+
+// txFor returns the unit of work ctx runs in if it holds store's lock
+func txFor(ctx context.Context, store participant) *tx {
+	t, _ := ctx.Value(txKey{}).(*tx)
+	if t == nil || !t.held[store] {
+		return nil
+	}
+	return t
+}
+
+// rollback reverts the writes made in the unit of work, latest first
+func (t *tx) rollback() {
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		t.undo[i]()
+	}
+	t.undo = nil
+}
+
+// UnitOfWork implements the UnitOfWork interface for in-memory repositories.
+// A unit of work holds the write lock of every participating repository
+// while it runs, so other callers wait for it to finish, and undoes its own
+// writes when fn fails. fn must therefore not wait for calls made to those
+// repositories without its context.
+type UnitOfWork struct {
+	stores []participant
+}
+
+// NewUnitOfWork creates a unit of work spanning the given repositories, which
+// must have been created by this package. Units of work sharing repositories
+// must list them in the same order.
+func NewUnitOfWork(repos ...ports.UserRepository) (ports.UnitOfWork, error) {
+	stores := make([]participant, len(repos))
+	for i, repo := range repos {
+		s, ok := repo.(participant)
+		if !ok {
+			return nil, fmt.Errorf("memory: %T cannot take part in a unit of work", repo)
+		}
+		stores[i] = s
+	}
+	return &UnitOfWork{
+		stores: stores,
+	}, nil
+}
+
+// WithinTx runs fn, rolling back its writes if it returns an error or
+// panics. Nested calls join the outer unit of work.
+func (u *UnitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	t := &tx{held: make(map[participant]bool, len(u.stores))}
+	for _, s := range u.stores {
+		if t.held[s] {
+			continue
+		}
+		locker := s.txLocker()
+		locker.Lock()
+		defer locker.Unlock()
+		t.held[s] = true
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			t.rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, t)); err != nil {
+		t.rollback()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/memory"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports/porttest"
+)
+
+func TestUnitOfWork(t *testing.T) {
+	porttest.RunUnitOfWorkTests(t, func(t *testing.T) (ports.UserRepository, ports.UnitOfWork) {
+		repo := memory.NewUserRepository()
+		uow, err := memory.NewUnitOfWork(repo)
+		if err != nil {
+			t.Fatalf("NewUnitOfWork() error = %v", err)
+		}
+		return repo, uow
+	})
+}
+
+func TestNewUnitOfWorkRejectsForeignRepositories(t *testing.T) {
+	if _, err := memory.NewUnitOfWork(foreignRepository{}); err == nil {
+		t.Errorf("NewUnitOfWork() with a repository from another package error = nil, want an error")
+	}
+}
+
+// foreignRepository is a repository this package cannot take part in a
+// unit of work
+type foreignRepository struct {
+	ports.UserRepository
+}
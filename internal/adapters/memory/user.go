@@ -0,0 +1,402 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+// UserRepository implements the UserRepository interface in memory. It
+// enforces the same unique email rule and ordering as the PostgreSQL adapter.
+type UserRepository struct {
+	mu      sync.RWMutex
+	users   map[string]*domain.User
+	byEmail map[string]string
+}
+
+// NewUserRepository creates a new in-memory user repository
+func NewUserRepository() ports.UserRepository {
+	return &UserRepository{
+		users:   make(map[string]*domain.User),
+		byEmail: make(map[string]string),
+	}
+}
+
+// Create creates a new user
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	defer r.lock(ctx)()
+
+	if _, ok := r.users[user.ID]; ok {
+		return domain.ErrUserAlreadyExists
+	}
+	if _, ok := r.byEmail[user.Email]; ok {
+		return domain.ErrUserAlreadyExists
+	}
+
+	u := *user
+	u.Version = 1
+	r.putUser(ctx, u.ID, &u)
+	r.putEmail(ctx, u.Email, u.ID)
+	return nil
+}
+
+// BulkCreate creates users, skipping those whose email is taken
+func (r *UserRepository) BulkCreate(ctx context.Context, users []*domain.User) ([]domain.BulkCreateStatus, error) {
+	defer r.lock(ctx)()
+
+	statuses := make([]domain.BulkCreateStatus, len(users))
+	for i, user := range users {
+		_, idTaken := r.users[user.ID]
+		_, emailTaken := r.byEmail[user.Email]
+		if idTaken || emailTaken {
+			statuses[i] = domain.BulkCreateDuplicate
+			continue
+		}
+
+		u := *user
+		u.Version = 1
+		r.putUser(ctx, u.ID, &u)
+		r.putEmail(ctx, u.Email, u.ID)
+		statuses[i] = domain.BulkCreateCreated
+	}
+	return statuses, nil
+}
+
+// GetByID retrieves a user by ID
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	defer r.rlock(ctx)()
+
+	user, ok := r.users[id]
+	if !ok || user.IsDeleted() {
+		return nil, domain.ErrUserNotFound
+	}
+
+	u := *user
+	return &u, nil
+}
+
+// GetByIDIncludingDeleted retrieves a user by ID even if it was soft-deleted
+func (r *UserRepository) GetByIDIncludingDeleted(ctx context.Context, id string) (*domain.User, error) {
+	defer r.rlock(ctx)()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+
+	u := *user
+	return &u, nil
+}
+
+// GetByEmail retrieves a user by email
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	defer r.rlock(ctx)()
+
+	id, ok := r.byEmail[email]
+	if !ok || r.users[id].IsDeleted() {
+		return nil, domain.ErrUserNotFound
+	}
+
+	u := *r.users[id]
+	return &u, nil
+}
+
+// TakenEmails returns those of emails that belong to a user, deleted or not
+func (r *UserRepository) TakenEmails(ctx context.Context, emails []string) ([]string, error) {
+	defer r.rlock(ctx)()
+
+	taken := []string{}
+	for _, email := range emails {
+		if _, ok := r.byEmail[email]; ok {
+			taken = append(taken, email)
+		}
+	}
+	return taken, nil
+}
+
+// List retrieves a list of users
+func (r *UserRepository) List(ctx context.Context, filter domain.UserFilter, order domain.UserSort, limit, offset int) ([]*domain.User, error) {
+	defer r.rlock(ctx)()
+
+	users := r.sorted(filter, order)
+
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(users) {
+		return []*domain.User{}, nil
+	}
+	users = users[offset:]
+	if limit >= 0 && limit < len(users) {
+		users = users[:limit]
+	}
+
+	return users, nil
+}
+
+// ListAfter retrieves the users following a cursor in the given order
+func (r *UserRepository) ListAfter(ctx context.Context, filter domain.UserFilter, order domain.UserSort, after *domain.UserCursor, limit int) ([]*domain.User, error) {
+	defer r.rlock(ctx)()
+
+	users := r.sorted(filter, order)
+
+	if after != nil {
+		// The cursor only carries the sort key, which is all less compares
+		pos := &domain.User{ID: after.ID, CreatedAt: after.CreatedAt, Name: after.Name, Email: after.Email}
+		start := sort.Search(len(users), func(i int) bool {
+			return less(order, pos, users[i])
+		})
+		users = users[start:]
+	}
+	if limit >= 0 && limit < len(users) {
+		users = users[:limit]
+	}
+
+	return users, nil
+}
+
+// Count counts the users matching filter
+func (r *UserRepository) Count(ctx context.Context, filter domain.UserFilter) (int64, error) {
+	defer r.rlock(ctx)()
+
+	var n int64
+	for _, user := range r.users {
+		if matches(filter, user) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// EstimateCount returns the exact count, which is cheap in memory
+func (r *UserRepository) EstimateCount(ctx context.Context, filter domain.UserFilter) (int64, error) {
+	return r.Count(ctx, filter)
+}
+
+// Search ranks the users that have not been deleted against query
+func (r *UserRepository) Search(ctx context.Context, query string, limit int) ([]*domain.UserSearchResult, error) {
+	defer r.rlock(ctx)()
+
+	return domain.RankUsers(r.sorted(domain.UserFilter{}, domain.DefaultUserSort), query, limit), nil
+}
+
+// sorted returns copies of the users matching filter in the given order. The
+// caller must hold r.mu.
+func (r *UserRepository) sorted(filter domain.UserFilter, order domain.UserSort) []*domain.User {
+	users := make([]*domain.User, 0, len(r.users))
+	for _, user := range r.users {
+		if !matches(filter, user) {
+			continue
+		}
+		u := *user
+		users = append(users, &u)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return less(order, users[i], users[j])
+	})
+
+	return users
+}
+
+// matches reports whether user passes filter
+func matches(filter domain.UserFilter, user *domain.User) bool {
+	if user.IsDeleted() && !filter.IncludeDeleted {
+		return false
+	}
+	if !strings.HasPrefix(strings.ToLower(user.Email), strings.ToLower(filter.EmailPrefix)) {
+		return false
+	}
+	if !strings.Contains(strings.ToLower(user.Name), strings.ToLower(filter.NameContains)) {
+		return false
+	}
+	return inRange(user.CreatedAt, filter.CreatedAfter, filter.CreatedBefore) &&
+		inRange(user.UpdatedAt, filter.UpdatedAfter, filter.UpdatedBefore)
+}
+
+// inRange reports whether t lies in [after, before), where nil bounds are open
+func inRange(t time.Time, after, before *time.Time) bool {
+	if after != nil && t.Before(*after) {
+		return false
+	}
+	if before != nil && !t.Before(*before) {
+		return false
+	}
+	return true
+}
+
+// less reports whether a sorts before b, breaking ties by ID
+func less(order domain.UserSort, a, b *domain.User) bool {
+	var c int
+	switch order.Field {
+	case domain.UserSortName:
+		c = strings.Compare(a.Name, b.Name)
+	case domain.UserSortEmail:
+		c = strings.Compare(a.Email, b.Email)
+	default:
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	}
+	if c == 0 {
+		c = strings.Compare(a.ID, b.ID)
+	}
+	if order.Descending {
+		return c > 0
+	}
+	return c < 0
+}
+
+// Update updates a user. It fails with domain.ErrConflict if
+// input.ExpectedVersion is stale.
+func (r *UserRepository) Update(ctx context.Context, id string, input *domain.UpdateUserInput) (*domain.User, error) {
+	defer r.lock(ctx)()
+
+	user, ok := r.users[id]
+	if !ok || user.IsDeleted() {
+		return nil, domain.ErrUserNotFound
+	}
+
+	if input.ExpectedVersion != nil && *input.ExpectedVersion != user.Version {
+		return nil, domain.ErrConflict
+	}
+
+	u := *user
+	if input.Email != nil {
+		u.Email = *input.Email
+	}
+	if input.Name != nil {
+		u.Name = *input.Name
+	}
+	u.UpdatedAt = time.Now()
+	u.Version++
+
+	if u.Email != user.Email {
+		if _, taken := r.byEmail[u.Email]; taken {
+			return nil, domain.ErrUserAlreadyExists
+		}
+		r.putEmail(ctx, user.Email, "")
+		r.putEmail(ctx, u.Email, id)
+	}
+	r.putUser(ctx, id, &u)
+
+	result := u
+	return &result, nil
+}
+
+// Delete soft-deletes a user. The email stays reserved until the user is
+// purged, as with the unique index in the SQL adapters.
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	defer r.lock(ctx)()
+
+	user, ok := r.users[id]
+	if !ok || user.IsDeleted() {
+		return domain.ErrUserNotFound
+	}
+
+	u := *user
+	now := time.Now()
+	u.DeletedAt = &now
+	u.Version++
+	r.putUser(ctx, id, &u)
+	return nil
+}
+
+// Restore undoes a soft delete
+func (r *UserRepository) Restore(ctx context.Context, id string) (*domain.User, error) {
+	defer r.lock(ctx)()
+
+	user, ok := r.users[id]
+	if !ok || !user.IsDeleted() {
+		return nil, domain.ErrUserNotFound
+	}
+
+	u := *user
+	u.DeletedAt = nil
+	u.Version++
+	r.putUser(ctx, id, &u)
+
+	result := u
+	return &result, nil
+}
+
+// Purge permanently removes a user
+func (r *UserRepository) Purge(ctx context.Context, id string) error {
+	defer r.lock(ctx)()
+
+	user, ok := r.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+
+	r.putEmail(ctx, user.Email, "")
+	r.putUser(ctx, id, nil)
+	return nil
+}
+
+// lock write-locks r, unless ctx runs in a unit of work that holds the lock,
+// and returns the function unlocking it
+func (r *UserRepository) lock(ctx context.Context) func() {
+	if txFor(ctx, r) != nil {
+		return func() {}
+	}
+	r.mu.Lock()
+	return r.mu.Unlock
+}
+
+// rlock read-locks r like lock
+func (r *UserRepository) rlock(ctx context.Context) func() {
+	if txFor(ctx, r) != nil {
+		return func() {}
+	}
+	r.mu.RLock()
+	return r.mu.RUnlock
+}
+
+// txLocker returns the lock a unit of work holds
+func (r *UserRepository) txLocker() sync.Locker {
+	return &r.mu
+}
+
+// putUser stores user under id, or removes the user if it is nil. In a unit
+// of work it records how to undo the write. The caller must hold r.mu.
+func (r *UserRepository) putUser(ctx context.Context, id string, user *domain.User) {
+	if t := txFor(ctx, r); t != nil {
+		old, existed := r.users[id]
+		t.undo = append(t.undo, func() {
+			if existed {
+				r.users[id] = old
+			} else {
+				delete(r.users, id)
+			}
+		})
+	}
+	if user == nil {
+		delete(r.users, id)
+	} else {
+		r.users[id] = user
+	}
+}
+
+// putEmail reserves email for the user with ID id, or releases it if id is
+// empty, like putUser
+func (r *UserRepository) putEmail(ctx context.Context, email, id string) {
+	if t := txFor(ctx, r); t != nil {
+		old, existed := r.byEmail[email]
+		t.undo = append(t.undo, func() {
+			if existed {
+				r.byEmail[email] = old
+			} else {
+				delete(r.byEmail, email)
+			}
+		})
+	}
+	if id == "" {
+		delete(r.byEmail, email)
+	} else {
+		r.byEmail[email] = id
+	}
+}
@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/memory"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports/porttest"
+)
+
+func TestUserRepository(t *testing.T) {
+	porttest.RunUserRepositoryTests(t, func(t *testing.T) ports.UserRepository {
+		return memory.NewUserRepository()
+	})
+}
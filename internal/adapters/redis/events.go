@@ -0,0 +1,246 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultEventStream is the Redis stream holding user events
+	DefaultEventStream = "users:events"
+	// DefaultEventHistory is the approximate number of events retained
+	DefaultEventHistory = 10000
+
+	// eventField is the stream entry field holding the JSON event
+	eventField = "event"
+	// eventReadBlock is how long a read waits for new events before
+	// checking whether its context is done
+	eventReadBlock = 2 * time.Second
+	// eventReadCount is the number of events fetched per read
+	eventReadCount = 100
+)
+
+// UserEventBus implements the UserEventBus interface with a Redis stream,
+// so that events published by one instance reach watchers on every
+// instance. Event IDs are stream entry IDs. The stream is trimmed to about
+// history entries.
+type UserEventBus struct {
+	client  *redis.Client
+	stream  string
+	history int64
+}
+
+// NewUserEventBus creates an event bus on the Redis stream named stream
+func NewUserEventBus(client *redis.Client, stream string, history int64) ports.UserEventBus {
+	if stream == "" {
+		stream = DefaultEventStream
+	}
+	if history <= 0 {
+		history = DefaultEventHistory
+	}
+	return &UserEventBus{client: client, stream: stream, history: history}
+}
+
+// Publish appends the events to the stream in one pipeline, trimming it to
+// about history entries, and sets their IDs to the stream entry IDs
+func (b *UserEventBus) Publish(ctx context.Context, events ...*domain.UserEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	cmds := make([]*redis.StringCmd, len(events))
+	_, err := b.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, event := range events {
+			// The ID is assigned by Redis
+			stored := *event
+			stored.ID = ""
+			data, err := json.Marshal(&stored)
+			if err != nil {
+				return err
+			}
+			cmds[i] = pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: b.stream,
+				MaxLen: b.history,
+				Approx: true,
+				Values: []string{eventField, string(data)},
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, cmd := range cmds {
+		events[i].ID = cmd.Val()
+	}
+	return nil
+}
+
+// Subscribe returns a stream reading the entries after the one with ID
+// after, or after the current last entry if after is empty
+func (b *UserEventBus) Subscribe(ctx context.Context, after string) (ports.UserEventStream, error) {
+	if after == "" {
+		// Resolve the current end of the stream now, so that no event
+		// published after Subscribe returns is missed
+		last, err := b.client.XRevRangeN(ctx, b.stream, "+", "-", 1).Result()
+		if err != nil {
+			return nil, err
+		}
+		after = "0-0"
+		if len(last) > 0 {
+			after = last[0].ID
+		}
+		return &userEventStream{bus: b, last: after}, nil
+	}
+
+	cursor, ok := parseStreamID(after)
+	if !ok {
+		return nil, domain.ErrInvalidCursor
+	}
+	if err := b.checkRetained(ctx, cursor); err != nil {
+		return nil, err
+	}
+	return &userEventStream{bus: b, last: after}, nil
+}
+
+// checkRetained returns domain.ErrCursorExpired if events following the
+// entry with ID after may have been trimmed. Trimming removes the oldest
+// entries, so events after the cursor may be lost if it precedes the oldest
+// retained entry.
+func (b *UserEventBus) checkRetained(ctx context.Context, after streamID) error {
+	first, err := b.client.XRangeN(ctx, b.stream, "-", "+", 1).Result()
+	if err != nil {
+		return err
+	}
+	if len(first) > 0 {
+		oldest, _ := parseStreamID(first[0].ID)
+		if after.before(oldest) && !after.precedes(oldest) {
+			return domain.ErrCursorExpired
+		}
+	}
+	return nil
+}
+
+// userEventStream reads a Redis stream after the entry with ID last
+type userEventStream struct {
+	bus     *UserEventBus
+	last    string
+	pending []*domain.UserEvent
+}
+
+// Next returns the next event, reading up to eventReadCount entries at a
+// time. It returns domain.ErrCursorExpired once the stream has been trimmed
+// past the last event read.
+func (s *userEventStream) Next(ctx context.Context) (*domain.UserEvent, error) {
+	for len(s.pending) == 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// The stream may have been trimmed past the last event read. The
+		// zero ID of a stream that was empty on Subscribe precedes every
+		// entry, so it cannot be checked against the oldest one.
+		if last, _ := parseStreamID(s.last); last != (streamID{}) {
+			if err := s.bus.checkRetained(ctx, last); err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return nil, ctxErr
+				}
+				return nil, err
+			}
+		}
+		// Redis blocks for eventReadBlock, which bounds how late a done
+		// context is noticed
+		streams, err := s.bus.client.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{s.bus.stream, s.last},
+			Count:   eventReadCount,
+			Block:   eventReadBlock,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			return nil, err
+		}
+
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				event, err := decodeEvent(message)
+				if err != nil {
+					return nil, err
+				}
+				s.pending = append(s.pending, event)
+				s.last = message.ID
+			}
+		}
+	}
+
+	event := s.pending[0]
+	s.pending = s.pending[1:]
+	return event, nil
+}
+
+// Close does nothing, as a stream holds no Redis resources between reads
+func (s *userEventStream) Close() error {
+	return nil
+}
+
+// decodeEvent decodes the event held by a stream entry
+func decodeEvent(message redis.XMessage) (*domain.UserEvent, error) {
+	data, ok := message.Values[eventField].(string)
+	if !ok {
+		return nil, fmt.Errorf("stream entry %s has no %s field", message.ID, eventField)
+	}
+	var event domain.UserEvent
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return nil, fmt.Errorf("decoding stream entry %s: %w", message.ID, err)
+	}
+	event.ID = message.ID
+	return &event, nil
+}
+
+// streamID is a parsed Redis stream entry ID, "<milliseconds>-<sequence>"
+type streamID struct {
+	ms, seq uint64
+}
+
+func parseStreamID(id string) (streamID, bool) {
+	msPart, seqPart, ok := strings.Cut(id, "-")
+	if !ok {
+		return streamID{}, false
+	}
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return streamID{}, false
+	}
+	seq, err := strconv.ParseUint(seqPart, 10, 64)
+	if err != nil {
+		return streamID{}, false
+	}
+	return streamID{ms: ms, seq: seq}, true
+}
+
+// before reports whether id sorts before other
+func (id streamID) before(other streamID) bool {
+	return id.ms < other.ms || (id.ms == other.ms && id.seq < other.seq)
+}
+
+// precedes reports whether id is the ID immediately before other, in which
+// case no entry can lie between them
+func (id streamID) precedes(other streamID) bool {
+	if other.seq > 0 {
+		return id.ms == other.ms && id.seq == other.seq-1
+	}
+	return false
+}
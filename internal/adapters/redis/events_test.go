@@ -0,0 +1,33 @@
+package redis_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	redisadapter "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/redis"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports/porttest"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// TestUserEventBus runs against the Redis server at TEST_REDIS_ADDR. Each
+// subtest uses its own stream, which is deleted afterwards.
+func TestUserEventBus(t *testing.T) {
+	addr := os.Getenv("TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("TEST_REDIS_ADDR not set")
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { client.Close() })
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Fatalf("failed to connect to Redis: %v", err)
+	}
+
+	porttest.RunUserEventBusTests(t, func(t *testing.T, history int) ports.UserEventBus {
+		stream := "test:users:events:" + uuid.NewString()
+		t.Cleanup(func() { client.Del(context.Background(), stream) })
+		return redisadapter.NewUserEventBus(client, stream, int64(history))
+	})
+}
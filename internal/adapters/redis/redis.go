@@ -2,7 +2,7 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
@@ -22,17 +22,20 @@ func NewRedisRepository(client *redis.Client) ports.CacheRepository {
 }
 
 // Set stores a value in the cache
-func (r *RedisRepository) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
-	jsonData, err := json.Marshal(value)
-	if err != nil {
-		return err
-	}
-	return r.client.Set(ctx, key, jsonData, expiration).Err()
+func (r *RedisRepository) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	return r.client.Set(ctx, key, value, expiration).Err()
 }
 
 // Get retrieves a value from the cache
-func (r *RedisRepository) Get(ctx context.Context, key string) (string, error) {
-	return r.client.Get(ctx, key).Result()
+func (r *RedisRepository) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := r.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ports.ErrCacheMiss
+		}
+		return nil, err
+	}
+	return value, nil
 }
 
 // Delete removes a value from the cache
@@ -48,3 +51,40 @@ func (r *RedisRepository) Exists(ctx context.Context, key string) (bool, error)
 	}
 	return count > 0, nil
 }
+
+// GetMany retrieves several values with a single MGET
+func (r *RedisRepository) GetMany(ctx context.Context, keys []string) (map[string][]byte, error) {
+	result := make(map[string][]byte, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, value := range values {
+		// MGET replies with nil for missing keys and strings otherwise
+		if s, ok := value.(string); ok {
+			result[keys[i]] = []byte(s)
+		}
+	}
+	return result, nil
+}
+
+// SetMany stores several values in one pipeline. MSET is not used as it
+// cannot set an expiration.
+func (r *RedisRepository) SetMany(ctx context.Context, values map[string][]byte, expiration time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, value := range values {
+			pipe.Set(ctx, key, value, expiration)
+		}
+		return nil
+	})
+	return err
+}
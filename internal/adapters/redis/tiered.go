@@ -0,0 +1,263 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultInvalidationChannel is the pub/sub channel used to broadcast evictions
+	DefaultInvalidationChannel = "cache:invalidate"
+	// DefaultLocalTTL is how long entries are kept in the local tier
+	DefaultLocalTTL = 30 * time.Second
+)
+
+// invalidation is the message published whenever a key changes
+type invalidation struct {
+	Origin string `json:"origin"`
+	Key    string `json:"key"`
+}
+
+// TieredRepository implements the CacheRepository interface with a small
+// per-process LRU in front of Redis. Writes and deletes are broadcast over
+// Redis pub/sub so every instance evicts its local copy. Entries are kept
+// locally for at most localTTL, which bounds staleness if a broadcast is lost.
+type TieredRepository struct {
+	remote   *RedisRepository
+	local    ports.CacheRepository
+	localTTL time.Duration
+	channel  string
+	origin   string
+	pubsub   *redis.PubSub
+	done     chan struct{}
+	wg       sync.WaitGroup
+
+	// mu orders changes to the local tier. generation counts the keys
+	// changed or invalidated, so that a value read from Redis is not stored
+	// locally if a key changed while it was read.
+	mu         sync.Mutex
+	generation uint64
+}
+
+// NewTieredRepository creates a two-tier cache with local in front of Redis
+// and starts listening for invalidations on channel. local should be an
+// in-process cache such as memory.NewCacheRepository. Close must be called
+// to stop the listener.
+func NewTieredRepository(client *redis.Client, local ports.CacheRepository, localTTL time.Duration, channel string) (*TieredRepository, error) {
+	if localTTL <= 0 {
+		localTTL = DefaultLocalTTL
+	}
+	if channel == "" {
+		channel = DefaultInvalidationChannel
+	}
+
+	pubsub := client.Subscribe(context.Background(), channel)
+	// Wait for the subscription to be confirmed so no invalidation is missed
+	if _, err := pubsub.Receive(context.Background()); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	t := &TieredRepository{
+		remote:   &RedisRepository{client: client},
+		local:    local,
+		localTTL: localTTL,
+		channel:  channel,
+		origin:   uuid.New().String(),
+		pubsub:   pubsub,
+		done:     make(chan struct{}),
+	}
+
+	t.wg.Add(1)
+	go t.listen()
+
+	return t, nil
+}
+
+// Set stores a value in Redis and in the local cache, then tells other
+// instances to drop their copy
+func (t *TieredRepository) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	if err := t.remote.Set(ctx, key, value, expiration); err != nil {
+		t.evict(ctx, key)
+		return err
+	}
+
+	t.store(ctx, map[string][]byte{key: value}, t.localExpiration(expiration))
+	return t.publish(ctx, key)
+}
+
+// Get retrieves a value from the local cache, falling back to Redis
+func (t *TieredRepository) Get(ctx context.Context, key string) ([]byte, error) {
+	if value, err := t.local.Get(ctx, key); err == nil {
+		return value, nil
+	}
+
+	generation := t.currentGeneration()
+	value, err := t.remote.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	t.fill(ctx, generation, map[string][]byte{key: value})
+	return value, nil
+}
+
+// Delete removes a value from both tiers and from every other instance
+func (t *TieredRepository) Delete(ctx context.Context, key string) error {
+	t.evict(ctx, key)
+	if err := t.remote.Delete(ctx, key); err != nil {
+		return err
+	}
+	return t.publish(ctx, key)
+}
+
+// Exists checks if a key exists in either tier
+func (t *TieredRepository) Exists(ctx context.Context, key string) (bool, error) {
+	if ok, _ := t.local.Exists(ctx, key); ok {
+		return true, nil
+	}
+	return t.remote.Exists(ctx, key)
+}
+
+// GetMany retrieves several values, only asking Redis for the keys missing
+// from the local cache
+func (t *TieredRepository) GetMany(ctx context.Context, keys []string) (map[string][]byte, error) {
+	result, _ := t.local.GetMany(ctx, keys)
+
+	missing := make([]string, 0, len(keys)-len(result))
+	for _, key := range keys {
+		if _, ok := result[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	generation := t.currentGeneration()
+	remote, err := t.remote.GetMany(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	t.fill(ctx, generation, remote)
+
+	for key, value := range remote {
+		result[key] = value
+	}
+	return result, nil
+}
+
+// SetMany stores several values in both tiers and tells other instances to
+// drop their copies
+func (t *TieredRepository) SetMany(ctx context.Context, values map[string][]byte, expiration time.Duration) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	if err := t.remote.SetMany(ctx, values, expiration); err != nil {
+		t.evict(ctx, keys...)
+		return err
+	}
+
+	t.store(ctx, values, t.localExpiration(expiration))
+	return t.publish(ctx, keys...)
+}
+
+// Close stops listening for invalidations
+func (t *TieredRepository) Close() error {
+	close(t.done)
+	err := t.pubsub.Close()
+	t.wg.Wait()
+	return err
+}
+
+// currentGeneration returns the generation to pass to fill for a read from
+// Redis starting now
+func (t *TieredRepository) currentGeneration() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.generation
+}
+
+// fill stores values read from Redis locally, unless a key changed since
+// generation, in which case they may be stale
+func (t *TieredRepository) fill(ctx context.Context, generation uint64, values map[string][]byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.generation == generation {
+		t.local.SetMany(ctx, values, t.localTTL)
+	}
+}
+
+// store stores values just written to Redis locally
+func (t *TieredRepository) store(ctx context.Context, values map[string][]byte, expiration time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.generation++
+	t.local.SetMany(ctx, values, expiration)
+}
+
+// evict drops keys from the local tier
+func (t *TieredRepository) evict(ctx context.Context, keys ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.generation++
+	for _, key := range keys {
+		t.local.Delete(ctx, key)
+	}
+}
+
+// localExpiration caps an expiration at the local TTL
+func (t *TieredRepository) localExpiration(expiration time.Duration) time.Duration {
+	if expiration <= 0 || expiration > t.localTTL {
+		return t.localTTL
+	}
+	return expiration
+}
+
+// publish broadcasts that keys changed
+func (t *TieredRepository) publish(ctx context.Context, keys ...string) error {
+	_, err := t.remote.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			msg, err := json.Marshal(invalidation{Origin: t.origin, Key: key})
+			if err != nil {
+				return err
+			}
+			pipe.Publish(ctx, t.channel, msg)
+		}
+		return nil
+	})
+	return err
+}
+
+// listen evicts keys invalidated by other instances
+func (t *TieredRepository) listen() {
+	defer t.wg.Done()
+
+	ch := t.pubsub.Channel()
+	for {
+		select {
+		case <-t.done:
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var inv invalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+				continue
+			}
+			if inv.Origin == t.origin {
+				continue
+			}
+			t.evict(context.Background(), inv.Key)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/memory"
+	redisadapter "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/redis"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports/porttest"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newClient connects a client to server
+func newClient(t *testing.T, server *miniredis.Miniredis) *redis.Client {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// newTiered creates a tiered cache over client with local as its local tier
+func newTiered(t *testing.T, client *redis.Client, local ports.CacheRepository) *redisadapter.TieredRepository {
+	t.Helper()
+	tiered, err := redisadapter.NewTieredRepository(client, local, time.Minute, "")
+	if err != nil {
+		t.Fatalf("NewTieredRepository() error = %v", err)
+	}
+	t.Cleanup(func() { tiered.Close() })
+	return tiered
+}
+
+func TestRedisRepository(t *testing.T) {
+	porttest.RunCacheRepositoryTests(t, func(t *testing.T) ports.CacheRepository {
+		return redisadapter.NewRedisRepository(newClient(t, miniredis.RunT(t)))
+	})
+}
+
+func TestTieredRepository(t *testing.T) {
+	porttest.RunCacheRepositoryTests(t, func(t *testing.T) ports.CacheRepository {
+		return newTiered(t, newClient(t, miniredis.RunT(t)), memory.NewCacheRepository(100))
+	})
+}
+
+func TestTieredRepositoryServesLocalCopies(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	tiered := newTiered(t, newClient(t, server), memory.NewCacheRepository(100))
+	if err := tiered.Set(ctx, "a", []byte("1"), time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	// Removing the key from Redis alone leaves the local copy in place
+	server.Del("a")
+	if got, err := tiered.Get(ctx, "a"); err != nil || string(got) != "1" {
+		t.Errorf("Get() of a local copy = %q, %v, want %q", got, err, "1")
+	}
+
+	if err := tiered.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := tiered.Get(ctx, "a"); !errors.Is(err, ports.ErrCacheMiss) {
+		t.Errorf("Get() after Delete() error = %v, want %v", err, ports.ErrCacheMiss)
+	}
+}
+
+func TestTieredRepositoryInvalidatesOtherInstances(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	writer := newTiered(t, newClient(t, server), memory.NewCacheRepository(100))
+	readers := []*redisadapter.TieredRepository{
+		newTiered(t, newClient(t, server), memory.NewCacheRepository(100)),
+		newTiered(t, newClient(t, server), memory.NewCacheRepository(100)),
+	}
+
+	if err := writer.SetMany(ctx, map[string][]byte{"a": []byte("1"), "b": []byte("1")}, time.Minute); err != nil {
+		t.Fatalf("SetMany() error = %v", err)
+	}
+	// Fill every reader's local tier
+	for _, reader := range readers {
+		if got, err := reader.GetMany(ctx, []string{"a", "b"}); err != nil || len(got) != 2 {
+			t.Fatalf("GetMany() = %q, %v, want both keys", got, err)
+		}
+	}
+
+	if err := writer.Set(ctx, "a", []byte("2"), time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := writer.Delete(ctx, "b"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	for i, reader := range readers {
+		eventually(t, func() bool {
+			a, err := reader.Get(ctx, "a")
+			if err != nil || string(a) != "2" {
+				return false
+			}
+			_, err = reader.Get(ctx, "b")
+			return errors.Is(err, ports.ErrCacheMiss)
+		}, "reader %d did not drop its local copies", i)
+	}
+}
+
+// signalingCache reports each Delete on its deleted channel
+type signalingCache struct {
+	ports.CacheRepository
+	deleted chan string
+}
+
+func (c *signalingCache) Delete(ctx context.Context, key string) error {
+	err := c.CacheRepository.Delete(ctx, key)
+	c.deleted <- key
+	return err
+}
+
+// afterGetHook runs fn once, after the first GET command completes
+type afterGetHook struct {
+	once sync.Once
+	fn   func()
+}
+
+func (h *afterGetHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *afterGetHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		if strings.EqualFold(cmd.Name(), "get") {
+			h.once.Do(h.fn)
+		}
+		return err
+	}
+}
+
+func (h *afterGetHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestTieredRepositoryDropsFillRacingInvalidation(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	writer := newTiered(t, newClient(t, server), memory.NewCacheRepository(100))
+	local := &signalingCache{CacheRepository: memory.NewCacheRepository(100), deleted: make(chan string, 10)}
+	readerClient := newClient(t, server)
+	reader := newTiered(t, readerClient, local)
+
+	if err := writer.Set(ctx, "a", []byte("1"), time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	waitDeleted(t, local, "a")
+
+	// Change the key after the reader has read it from Redis but before it
+	// stores it locally
+	readerClient.AddHook(&afterGetHook{fn: func() {
+		if err := writer.Set(ctx, "a", []byte("2"), time.Minute); err != nil {
+			t.Errorf("Set() error = %v", err)
+		}
+		waitDeleted(t, local, "a")
+	}})
+	if got, err := reader.Get(ctx, "a"); err != nil || string(got) != "1" {
+		t.Fatalf("Get() = %q, %v, want %q", got, err, "1")
+	}
+
+	if got, err := reader.Get(ctx, "a"); err != nil || string(got) != "2" {
+		t.Errorf("Get() after an invalidation raced a fill = %q, %v, want %q", got, err, "2")
+	}
+}
+
+// waitDeleted waits for the invalidation of key to reach local
+func waitDeleted(t *testing.T, local *signalingCache, key string) {
+	t.Helper()
+	select {
+	case got := <-local.deleted:
+		if got != key {
+			t.Fatalf("deleted key = %q, want %q", got, key)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("invalidation of %q was not received", key)
+	}
+}
+
+// eventually fails the test unless cond holds within a few seconds
+func eventually(t *testing.T, cond func() bool, format string, args ...any) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf(format, args...)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,26 @@
+package sqlite
+
+import (
+	"errors"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// translateError converts constraint violations to domain errors, as the
+// PostgreSQL adapter does. Other errors are returned unchanged.
+func translateError(err error) error {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return domain.ErrUserAlreadyExists
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY, sqlite3.SQLITE_CONSTRAINT_CHECK:
+		return domain.ErrInvalidInput
+	}
+	return err
+}
@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/sqlquery"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+)
+
+// ExplainListAfter returns SQLite's plan for the query ListAfter runs, one
+// step per line
+func ExplainListAfter(ctx context.Context, db *sql.DB, filter domain.UserFilter, sort domain.UserSort, after *domain.UserCursor) (string, error) {
+	q := sqlquery.NewUserQuery(dialect, filter)
+	if after != nil {
+		q.After(sort, after)
+	}
+	query := q.ListSQL(sort, 10, 0)
+	rows, err := db.QueryContext(ctx, "EXPLAIN QUERY PLAN "+query, q.Args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var steps []string
+	for rows.Next() {
+		var id, parent, unused int
+		var detail string
+		if err := rows.Scan(&id, &parent, &unused, &detail); err != nil {
+			return "", err
+		}
+		steps = append(steps, detail)
+	}
+	return strings.Join(steps, "\n"), rows.Err()
+}
@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	sqlcdb "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/sqlite/sqlc"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/sqlquery"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+)
+
+// dialect passes the listing query parameters as SQLite expects, with
+// timestamps in UTC as they are stored
+var dialect = sqlquery.Dialect{
+	Placeholder: func(int) string { return "?" },
+	Time:        func(t time.Time) any { return t.UTC() },
+}
+
+// listUsers runs a listing query built by sqlquery.UserQuery.ListSQL,
+// scanning its sqlquery.UserColumns
+func listUsers(ctx context.Context, db sqlcdb.DBTX, query string, args []any) ([]*domain.User, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []*domain.User{}
+	for rows.Next() {
+		var u sqlcdb.User
+		if err := rows.Scan(&u.ID, &u.Email, &u.Name, &u.CreatedAt, &u.UpdatedAt, &u.Version, &u.DeletedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, toDomainUser(u))
+	}
+	return users, rows.Err()
+}
@@ -0,0 +1,31 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+
+package db
+
+import (
+	"context"
+)
+
+type Querier interface {
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserIfAbsent(ctx context.Context, arg CreateUserIfAbsentParams) (int64, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserByID(ctx context.Context, id string) (User, error)
+	GetUserByIDIncludingDeleted(ctx context.Context, id string) (User, error)
+	PurgeUser(ctx context.Context, id string) (int64, error)
+	RestoreUser(ctx context.Context, id string) (User, error)
+	// Users whose lowercased name or email contains any of the JSON array of
+	// fragments, newest first; they are ranked in Go as SQLite lacks pg_trgm.
+	// The fragments go through a CTE because sqlc does not bind parameters in
+	// table-valued functions for SQLite.
+	SearchCandidates(ctx context.Context, arg SearchCandidatesParams) ([]User, error)
+	SoftDeleteUser(ctx context.Context, arg SoftDeleteUserParams) (int64, error)
+	// The emails are a JSON array, which goes through a CTE because sqlc does
+	// not bind parameters in table-valued functions for SQLite
+	TakenEmails(ctx context.Context, emails string) ([]string, error)
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+}
+
+var _ Querier = (*Queries)(nil)
@@ -0,0 +1,318 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: users.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createUser = `-- name: CreateUser :one
+INSERT INTO users (id, email, name, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id, email, name, created_at, updated_at, version, deleted_at
+`
+
+type CreateUserParams struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, createUser,
+		arg.ID,
+		arg.Email,
+		arg.Name,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const createUserIfAbsent = `-- name: CreateUserIfAbsent :execrows
+INSERT INTO users (id, email, name, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT DO NOTHING
+`
+
+type CreateUserIfAbsentParams struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (q *Queries) CreateUserIfAbsent(ctx context.Context, arg CreateUserIfAbsentParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createUserIfAbsent,
+		arg.ID,
+		arg.Email,
+		arg.Name,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, email, name, created_at, updated_at, version, deleted_at FROM users
+WHERE email = ? AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, email, name, created_at, updated_at, version, deleted_at FROM users
+WHERE id = ? AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getUserByIDIncludingDeleted = `-- name: GetUserByIDIncludingDeleted :one
+SELECT id, email, name, created_at, updated_at, version, deleted_at FROM users
+WHERE id = ?
+`
+
+func (q *Queries) GetUserByIDIncludingDeleted(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByIDIncludingDeleted, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const purgeUser = `-- name: PurgeUser :execrows
+DELETE FROM users
+WHERE id = ?
+`
+
+func (q *Queries) PurgeUser(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL,
+    version = version + 1
+WHERE id = ? AND deleted_at IS NOT NULL
+RETURNING id, email, name, created_at, updated_at, version, deleted_at
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRowContext(ctx, restoreUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const searchCandidates = `-- name: SearchCandidates :many
+WITH search AS (
+  SELECT CAST(?2 AS TEXT) AS fragments
+)
+SELECT users.id, users.email, users.name, users.created_at, users.updated_at, users.version, users.deleted_at FROM users, search
+WHERE deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM json_each(search.fragments) AS fragment
+    WHERE instr(lower(users.name), fragment.value) > 0
+       OR instr(lower(users.email), fragment.value) > 0
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT ?1
+`
+
+type SearchCandidatesParams struct {
+	Limit     int64  `json:"limit"`
+	Fragments string `json:"fragments"`
+}
+
+// Users whose lowercased name or email contains any of the JSON array of
+// fragments, newest first; they are ranked in Go as SQLite lacks pg_trgm.
+// The fragments go through a CTE because sqlc does not bind parameters in
+// table-valued functions for SQLite.
+func (q *Queries) SearchCandidates(ctx context.Context, arg SearchCandidatesParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, searchCandidates, arg.Limit, arg.Fragments)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = ?,
+    version = version + 1
+WHERE id = ? AND deleted_at IS NULL
+`
+
+type SoftDeleteUserParams struct {
+	DeletedAt sql.NullTime `json:"deleted_at"`
+	ID        string       `json:"id"`
+}
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, arg SoftDeleteUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, softDeleteUser, arg.DeletedAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const takenEmails = `-- name: TakenEmails :many
+WITH taken AS (
+  SELECT CAST(?1 AS TEXT) AS emails
+)
+SELECT users.email FROM users, taken
+WHERE users.email IN (SELECT value FROM json_each(taken.emails))
+`
+
+// The emails are a JSON array, which goes through a CTE because sqlc does
+// not bind parameters in table-valued functions for SQLite
+func (q *Queries) TakenEmails(ctx context.Context, emails string) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, takenEmails, emails)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		items = append(items, email)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users
+SET email = ?,
+    name = ?,
+    updated_at = ?,
+    version = version + 1
+WHERE id = ? AND version = ? AND deleted_at IS NULL
+RETURNING id, email, name, created_at, updated_at, version, deleted_at
+`
+
+type UpdateUserParams struct {
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ID        string    `json:"id"`
+	Version   int64     `json:"version"`
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUser,
+		arg.Email,
+		arg.Name,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.Version,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,347 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	sqlcdb "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/sqlite/sqlc"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/sqlquery"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/trigram"
+	_ "modernc.org/sqlite"
+)
+
+// MaxSearchCandidates caps the number of users a search ranks
+const MaxSearchCandidates = 1000
+
+// SQLiteRepository implements the UserRepository interface using SQLite
+type SQLiteRepository struct {
+	db      *sql.DB
+	queries *sqlcdb.Queries
+	uow     ports.UnitOfWork
+}
+
+// NewSQLiteRepository creates a new SQLite repository. Calls made with a
+// context from SQLiteUnitOfWork.WithinTx run in that transaction.
+func NewSQLiteRepository(db *sql.DB) ports.UserRepository {
+	return &SQLiteRepository{
+		db:      db,
+		queries: sqlcdb.New(db),
+		uow:     NewSQLiteUnitOfWork(db),
+	}
+}
+
+// Open opens the SQLite database at path with the pragmas the repository
+// relies on. The database is created if it does not exist.
+func Open(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", DSN(path))
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer; serialising connections avoids
+	// SQLITE_BUSY errors under concurrent writes
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// DSN returns the driver connection string for the database at path
+func DSN(path string) string {
+	return "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)"
+}
+
+// Helper function to convert a sql.NullTime to *time.Time
+func fromNullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func toDomainUser(u sqlcdb.User) *domain.User {
+	return &domain.User{
+		ID:        u.ID,
+		Email:     u.Email,
+		Name:      u.Name,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+		Version:   u.Version,
+		DeletedAt: fromNullTime(u.DeletedAt),
+	}
+}
+
+// Create creates a new user
+func (r *SQLiteRepository) Create(ctx context.Context, user *domain.User) error {
+	_, err := queriesFor(ctx, r.queries).CreateUser(ctx, sqlcdb.CreateUserParams{
+		ID:        user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		CreatedAt: user.CreatedAt.UTC(),
+		UpdatedAt: user.UpdatedAt.UTC(),
+	})
+	return translateError(err)
+}
+
+// BulkCreate creates users in one transaction, skipping those whose email
+// is taken
+func (r *SQLiteRepository) BulkCreate(ctx context.Context, users []*domain.User) ([]domain.BulkCreateStatus, error) {
+	statuses := make([]domain.BulkCreateStatus, len(users))
+	err := r.uow.WithinTx(ctx, func(ctx context.Context) error {
+		queries := queriesFor(ctx, r.queries)
+		for i, user := range users {
+			rows, err := queries.CreateUserIfAbsent(ctx, sqlcdb.CreateUserIfAbsentParams{
+				ID:        user.ID,
+				Email:     user.Email,
+				Name:      user.Name,
+				CreatedAt: user.CreatedAt.UTC(),
+				UpdatedAt: user.UpdatedAt.UTC(),
+			})
+			if err != nil {
+				return err
+			}
+			statuses[i] = domain.BulkCreateCreated
+			if rows == 0 {
+				statuses[i] = domain.BulkCreateDuplicate
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return statuses, nil
+}
+
+// GetByID retrieves a user by ID
+func (r *SQLiteRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	user, err := queriesFor(ctx, r.queries).GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return toDomainUser(user), nil
+}
+
+// GetByIDIncludingDeleted retrieves a user by ID, even if soft-deleted
+func (r *SQLiteRepository) GetByIDIncludingDeleted(ctx context.Context, id string) (*domain.User, error) {
+	user, err := queriesFor(ctx, r.queries).GetUserByIDIncludingDeleted(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return toDomainUser(user), nil
+}
+
+// GetByEmail retrieves a user by email
+func (r *SQLiteRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	user, err := queriesFor(ctx, r.queries).GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return toDomainUser(user), nil
+}
+
+// TakenEmails returns those of emails that belong to a user, deleted or not
+func (r *SQLiteRepository) TakenEmails(ctx context.Context, emails []string) ([]string, error) {
+	encoded, err := json.Marshal(emails)
+	if err != nil {
+		return nil, err
+	}
+	return queriesFor(ctx, r.queries).TakenEmails(ctx, string(encoded))
+}
+
+// List retrieves a list of users
+func (r *SQLiteRepository) List(ctx context.Context, filter domain.UserFilter, sort domain.UserSort, limit, offset int) ([]*domain.User, error) {
+	q := sqlquery.NewUserQuery(dialect, filter)
+	query := q.ListSQL(sort, limit, offset)
+	return listUsers(ctx, dbFor(ctx, r.db), query, q.Args)
+}
+
+// ListAfter retrieves the users following a cursor in sort order
+func (r *SQLiteRepository) ListAfter(ctx context.Context, filter domain.UserFilter, sort domain.UserSort, after *domain.UserCursor, limit int) ([]*domain.User, error) {
+	q := sqlquery.NewUserQuery(dialect, filter)
+	if after != nil {
+		q.After(sort, after)
+	}
+	query := q.ListSQL(sort, limit, 0)
+	return listUsers(ctx, dbFor(ctx, r.db), query, q.Args)
+}
+
+// Count counts the users matching filter
+func (r *SQLiteRepository) Count(ctx context.Context, filter domain.UserFilter) (int64, error) {
+	q := sqlquery.NewUserQuery(dialect, filter)
+	var count int64
+	err := dbFor(ctx, r.db).QueryRowContext(ctx, q.SelectSQL("count(*)"), q.Args...).Scan(&count)
+	return count, err
+}
+
+// EstimateCount returns the exact count, as SQLite has no row estimates
+func (r *SQLiteRepository) EstimateCount(ctx context.Context, filter domain.UserFilter) (int64, error) {
+	return r.Count(ctx, filter)
+}
+
+// Search ranks the users that have not been deleted against query. SQLite
+// has no trigram support, so the query selects the newest
+// MaxSearchCandidates users that could match and ranks them in Go. As
+// SQLite only lowercases ASCII, names with other capital letters may be
+// missed.
+func (r *SQLiteRepository) Search(ctx context.Context, query string, limit int) ([]*domain.UserSearchResult, error) {
+	fragments := searchFragments(query)
+	if len(fragments) == 0 {
+		return []*domain.UserSearchResult{}, nil
+	}
+	encoded, err := json.Marshal(fragments)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := queriesFor(ctx, r.queries).SearchCandidates(ctx, sqlcdb.SearchCandidatesParams{
+		Fragments: string(encoded),
+		Limit:     MaxSearchCandidates,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*domain.User, len(rows))
+	for i, row := range rows {
+		users[i] = toDomainUser(row)
+	}
+	return domain.RankUsers(users, query, limit), nil
+}
+
+// searchFragments returns the runs of two or three letters that a user must
+// share with query to match it: the start and end of each query word and
+// every trigram within it, or the word itself if it is a single letter. A
+// user sharing none of them can share at most the leading letter of words
+// with query, which stays below the trigram similarity threshold.
+func searchFragments(query string) []string {
+	seen := make(map[string]bool)
+	var fragments []string
+	add := func(fragment []rune) {
+		if s := string(fragment); !seen[s] {
+			seen[s] = true
+			fragments = append(fragments, s)
+		}
+	}
+	for _, word := range trigram.Words(query) {
+		runes := []rune(word)
+		if len(runes) < 2 {
+			add(runes)
+			continue
+		}
+		add(runes[:2])
+		add(runes[len(runes)-2:])
+		for i := 0; i+3 <= len(runes); i++ {
+			add(runes[i : i+3])
+		}
+	}
+	return fragments
+}
+
+// Update updates a user. It fails with domain.ErrConflict if
+// input.ExpectedVersion is stale.
+func (r *SQLiteRepository) Update(ctx context.Context, id string, input *domain.UpdateUserInput) (*domain.User, error) {
+	var result *domain.User
+	err := r.uow.WithinTx(ctx, func(ctx context.Context) error {
+		queries := queriesFor(ctx, r.queries)
+
+		// Get current user
+		currentUser, err := queries.GetUserByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrUserNotFound
+			}
+			return err
+		}
+
+		if input.ExpectedVersion != nil && *input.ExpectedVersion != currentUser.Version {
+			return domain.ErrConflict
+		}
+
+		// Use current values if not provided in input
+		email := currentUser.Email
+		name := currentUser.Name
+
+		if input.Email != nil {
+			email = *input.Email
+		}
+		if input.Name != nil {
+			name = *input.Name
+		}
+
+		user, err := queries.UpdateUser(ctx, sqlcdb.UpdateUserParams{
+			ID:        id,
+			Email:     email,
+			Name:      name,
+			UpdatedAt: time.Now().UTC(),
+			Version:   currentUser.Version,
+		})
+		if err != nil {
+			// The row was read in this transaction, so a missing row means its
+			// version moved
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrConflict
+			}
+			return err
+		}
+
+		result = toDomainUser(user)
+		return nil
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return result, nil
+}
+
+// Delete soft-deletes a user
+func (r *SQLiteRepository) Delete(ctx context.Context, id string) error {
+	rows, err := queriesFor(ctx, r.queries).SoftDeleteUser(ctx, sqlcdb.SoftDeleteUserParams{
+		ID:        id,
+		DeletedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// Restore undoes a soft delete
+func (r *SQLiteRepository) Restore(ctx context.Context, id string) (*domain.User, error) {
+	user, err := queriesFor(ctx, r.queries).RestoreUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, translateError(err)
+	}
+	return toDomainUser(user), nil
+}
+
+// Purge permanently deletes a user
+func (r *SQLiteRepository) Purge(ctx context.Context, id string) error {
+	rows, err := queriesFor(ctx, r.queries).PurgeUser(ctx, id)
+	if err != nil {
+		return translateError(err)
+	}
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/sqlite"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports/porttest"
+)
+
+func TestSQLiteRepository(t *testing.T) {
+	porttest.RunUserRepositoryTests(t, func(t *testing.T) ports.UserRepository {
+		return sqlite.NewSQLiteRepository(openTestDB(t))
+	})
+}
+
+func TestSQLiteUnitOfWork(t *testing.T) {
+	porttest.RunUnitOfWorkTests(t, func(t *testing.T) (ports.UserRepository, ports.UnitOfWork) {
+		db := openTestDB(t)
+		return sqlite.NewSQLiteRepository(db), sqlite.NewSQLiteUnitOfWork(db)
+	})
+}
+
+// TestSQLiteSearchCandidates checks that the SQL prefilter of Search keeps
+// every user that ranking all users would match
+func TestSQLiteSearchCandidates(t *testing.T) {
+	ctx := context.Background()
+	repo := sqlite.NewSQLiteRepository(openTestDB(t))
+	names := []string{
+		"John Smith", "Jon Smithe", "Jane Doe", "Ann Smith", "Will Smithers",
+		"Zoe Li", "A B", "Bo", "Maria Garcia", "Mario Garza", "Li Wei", "Al",
+	}
+	var users []*domain.User
+	for i, name := range names {
+		user := &domain.User{
+			ID:        fmt.Sprint(i),
+			Email:     fmt.Sprintf("user%d@example.com", i),
+			Name:      name,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Version:   1,
+		}
+		if err := repo.Create(ctx, user); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		users = append(users, user)
+	}
+
+	for _, query := range []string{"jon smth", "smith", "a", "a b", "bo", "mari garc", "li", "example", "zzzz", "", "!!"} {
+		got, err := repo.Search(ctx, query, -1)
+		if err != nil {
+			t.Fatalf("Search(%q) error = %v", query, err)
+		}
+		want := domain.RankUsers(users, query, -1)
+		if len(got) != len(want) {
+			t.Errorf("Search(%q) returned %d users, want %d", query, len(got), len(want))
+			continue
+		}
+		for i := range want {
+			if got[i].User.ID != want[i].User.ID {
+				t.Errorf("Search(%q)[%d] = %s, want %s", query, i, got[i].User.Name, want[i].User.Name)
+			}
+		}
+	}
+}
+
+func TestSQLiteSearchCapsCandidates(t *testing.T) {
+	ctx := context.Background()
+	repo := sqlite.NewSQLiteRepository(openTestDB(t))
+	users := make([]*domain.User, sqlite.MaxSearchCandidates+1)
+	for i := range users {
+		users[i] = &domain.User{
+			ID:        fmt.Sprintf("%05d", i),
+			Email:     fmt.Sprintf("user%d@example.com", i),
+			Name:      "Ann Smith",
+			CreatedAt: time.Unix(int64(i), 0),
+			UpdatedAt: time.Unix(int64(i), 0),
+			Version:   1,
+		}
+	}
+	if _, err := repo.BulkCreate(ctx, users); err != nil {
+		t.Fatalf("BulkCreate() error = %v", err)
+	}
+
+	results, err := repo.Search(ctx, "ann smith", -1)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != sqlite.MaxSearchCandidates {
+		t.Fatalf("Search() returned %d users, want %d", len(results), sqlite.MaxSearchCandidates)
+	}
+	for _, result := range results {
+		if result.User.ID == users[0].ID {
+			t.Errorf("Search() ranked the oldest user, want only the newest %d", sqlite.MaxSearchCandidates)
+		}
+	}
+}
+
+// TestSQLiteListUsesIndexes checks that every listing walks an index in sort
+// order, seeking to the cursor, instead of sorting the matching users
+func TestSQLiteListUsesIndexes(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	cursor := &domain.UserCursor{CreatedAt: time.Now(), Name: "name", Email: "email", ID: "id"}
+	for _, field := range []domain.UserSortField{domain.UserSortCreatedAt, domain.UserSortName, domain.UserSortEmail} {
+		for _, descending := range []bool{false, true} {
+			for _, includeDeleted := range []bool{false, true} {
+				sort := domain.UserSort{Field: field, Descending: descending}
+				filter := domain.UserFilter{IncludeDeleted: includeDeleted}
+
+				first, err := sqlite.ExplainListAfter(ctx, db, filter, sort, nil)
+				if err != nil {
+					t.Fatalf("ExplainListAfter() error = %v", err)
+				}
+				next, err := sqlite.ExplainListAfter(ctx, db, filter, sort, cursor)
+				if err != nil {
+					t.Fatalf("ExplainListAfter() error = %v", err)
+				}
+				if !strings.HasPrefix(first, "SCAN users USING INDEX") || !strings.HasPrefix(next, "SEARCH users USING INDEX") ||
+					strings.Contains(first+next, "TEMP B-TREE") {
+					t.Errorf("plans for %+v including deleted = %t:\n%s\n%s\nwant index scans in sort order", sort, includeDeleted, first, next)
+				}
+			}
+		}
+	}
+}
+
+// openTestDB opens a fresh, migrated database in a temporary directory
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := filepath.Glob("../../../migrations/sqlite/*.up.sql")
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("failed to find migrations: %v", err)
+	}
+	for _, path := range migrations {
+		schema, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		if _, err := db.ExecContext(context.Background(), string(schema)); err != nil {
+			t.Fatalf("failed to apply %s: %v", path, err)
+		}
+	}
+
+	return db
+}
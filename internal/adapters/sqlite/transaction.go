@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	sqlcdb "github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/sqlite/sqlc"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+// txKey is the context key under which the current transaction is stored
+type txKey struct{}
+
+// SQLiteUnitOfWork implements the UnitOfWork interface using database/sql
+// transactions. As Open allows a single connection, repository calls inside
+// fn must use the context passed to fn or they block until it returns.
+type SQLiteUnitOfWork struct {
+	db *sql.DB
+}
+
+// NewSQLiteUnitOfWork creates a new SQLite unit of work
+func NewSQLiteUnitOfWork(db *sql.DB) ports.UnitOfWork {
+	return &SQLiteUnitOfWork{
+		db: db,
+	}
+}
+
+// WithinTx runs fn inside a transaction, joining the one in ctx if present
+func (u *SQLiteUnitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// dbFor returns the transaction in ctx, if any, or db
+func dbFor(ctx context.Context, db *sql.DB) sqlcdb.DBTX {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// queriesFor returns queries bound to the transaction in ctx, if any
+func queriesFor(ctx context.Context, queries *sqlcdb.Queries) *sqlcdb.Queries {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return queries.WithTx(tx)
+	}
+	return queries
+}
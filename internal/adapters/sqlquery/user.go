@@ -0,0 +1,147 @@
+// Package sqlquery builds the user listing queries of the Postgres and
+// SQLite adapters. They are built here rather than by sqlc: a single static
+// query has to pick its sort order with CASE expressions, which keeps the
+// planner from walking the listing indexes. Only the column names below are
+// ever written into the SQL; every value is a parameter.
+package sqlquery
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+)
+
+// UserColumns are the columns a listing selects, in the order the adapters'
+// listUsers functions scan them
+const UserColumns = "id, email, name, created_at, updated_at, version, deleted_at"
+
+// Dialect describes how a database takes parameters
+type Dialect struct {
+	// Placeholder returns the placeholder of the nth parameter, counting
+	// from 1
+	Placeholder func(n int) string
+	// Time converts a timestamp to the parameter value compared with the
+	// timestamp columns
+	Time func(t time.Time) any
+}
+
+// sortColumns returns the columns ordering users by field, which match the
+// listing indexes. Emails are unique, so they order users on their own.
+func sortColumns(field domain.UserSortField) []string {
+	switch field {
+	case domain.UserSortName:
+		return []string{"name", "id"}
+	case domain.UserSortEmail:
+		return []string{"email"}
+	default:
+		return []string{"created_at", "id"}
+	}
+}
+
+// UserQuery builds a query over the users matching a filter
+type UserQuery struct {
+	dialect Dialect
+	where   []string
+	// Args are the parameters of the query, in placeholder order
+	Args []any
+}
+
+// NewUserQuery starts a query for the users matching filter. Only the
+// predicates the filter sets are added, so that the planner sees a plain
+// deleted_at IS NULL and can use the partial listing index.
+func NewUserQuery(dialect Dialect, filter domain.UserFilter) *UserQuery {
+	q := &UserQuery{dialect: dialect}
+	if !filter.IncludeDeleted {
+		q.where = append(q.where, "deleted_at IS NULL")
+	}
+	if filter.EmailPrefix != "" {
+		q.add("lower(email) LIKE %s ESCAPE '!'", escapeLike(filter.EmailPrefix)+"%")
+	}
+	if filter.NameContains != "" {
+		q.add("lower(name) LIKE %s ESCAPE '!'", "%"+escapeLike(filter.NameContains)+"%")
+	}
+	if filter.CreatedAfter != nil {
+		q.add("created_at >= %s", dialect.Time(*filter.CreatedAfter))
+	}
+	if filter.CreatedBefore != nil {
+		q.add("created_at < %s", dialect.Time(*filter.CreatedBefore))
+	}
+	if filter.UpdatedAfter != nil {
+		q.add("updated_at >= %s", dialect.Time(*filter.UpdatedAfter))
+	}
+	if filter.UpdatedBefore != nil {
+		q.add("updated_at < %s", dialect.Time(*filter.UpdatedBefore))
+	}
+	return q
+}
+
+// escapeLike lowercases s and escapes its LIKE wildcards, using '!' as the
+// escape character
+func escapeLike(s string) string {
+	return likeEscaper.Replace(strings.ToLower(s))
+}
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// param adds a parameter and returns its placeholder
+func (q *UserQuery) param(value any) string {
+	q.Args = append(q.Args, value)
+	return q.dialect.Placeholder(len(q.Args))
+}
+
+// add adds a predicate, replacing its %s with a placeholder for value
+func (q *UserQuery) add(predicate string, value any) {
+	q.where = append(q.where, fmt.Sprintf(predicate, q.param(value)))
+}
+
+// After restricts the query to the users following cursor in sort order.
+// The sort key is compared as a row value, which the index can seek to.
+func (q *UserQuery) After(sort domain.UserSort, cursor *domain.UserCursor) {
+	columns := sortColumns(sort.Field)
+	placeholders := make([]string, len(columns))
+	for i, column := range columns {
+		switch column {
+		case "created_at":
+			placeholders[i] = q.param(q.dialect.Time(cursor.CreatedAt))
+		case "name":
+			placeholders[i] = q.param(cursor.Name)
+		case "email":
+			placeholders[i] = q.param(cursor.Email)
+		case "id":
+			placeholders[i] = q.param(cursor.ID)
+		}
+	}
+	op := ">"
+	if sort.Descending {
+		op = "<"
+	}
+	q.where = append(q.where, fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), op, strings.Join(placeholders, ", ")))
+}
+
+// SelectSQL returns the query selecting columns from the matching users
+func (q *UserQuery) SelectSQL(columns string) string {
+	sql := "SELECT " + columns + " FROM users"
+	if len(q.where) > 0 {
+		sql += " WHERE " + strings.Join(q.where, " AND ")
+	}
+	return sql
+}
+
+// ListSQL returns the query listing a page of the matching users in sort
+// order. It adds the limit and offset to Args, so read Args after calling it.
+func (q *UserQuery) ListSQL(sort domain.UserSort, limit, offset int) string {
+	direction := " ASC"
+	if sort.Descending {
+		direction = " DESC"
+	}
+	columns := sortColumns(sort.Field)
+	order := make([]string, len(columns))
+	for i, column := range columns {
+		order[i] = column + direction
+	}
+	return q.SelectSQL(UserColumns) +
+		" ORDER BY " + strings.Join(order, ", ") +
+		" LIMIT " + q.param(limit) + " OFFSET " + q.param(offset)
+}
@@ -0,0 +1,85 @@
+package sqlquery_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/sqlquery"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+)
+
+var numbered = sqlquery.Dialect{
+	Placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+	Time:        func(t time.Time) any { return t },
+}
+
+func TestUserQueryListSQL(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cursor := &domain.UserCursor{ID: "u1", CreatedAt: createdAt, Name: "Ann", Email: "ann@example.com"}
+	tests := []struct {
+		name     string
+		filter   domain.UserFilter
+		sort     domain.UserSort
+		after    *domain.UserCursor
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "Default",
+			sort:     domain.DefaultUserSort,
+			wantSQL:  "SELECT " + sqlquery.UserColumns + " FROM users WHERE deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2",
+			wantArgs: []any{10, 0},
+		},
+		{
+			name:     "FilteredAfterCursor",
+			filter:   domain.UserFilter{IncludeDeleted: true, EmailPrefix: "A_n!", NameContains: "50%"},
+			sort:     domain.UserSort{Field: domain.UserSortName},
+			after:    cursor,
+			wantSQL:  "SELECT " + sqlquery.UserColumns + " FROM users WHERE lower(email) LIKE $1 ESCAPE '!' AND lower(name) LIKE $2 ESCAPE '!' AND (name, id) > ($3, $4) ORDER BY name ASC, id ASC LIMIT $5 OFFSET $6",
+			wantArgs: []any{"a!_n!!%", "%50!%%", "Ann", "u1", 10, 0},
+		},
+		{
+			name:     "EmailAfterCursor",
+			sort:     domain.UserSort{Field: domain.UserSortEmail, Descending: true},
+			after:    cursor,
+			wantSQL:  "SELECT " + sqlquery.UserColumns + " FROM users WHERE deleted_at IS NULL AND (email) < ($1) ORDER BY email DESC LIMIT $2 OFFSET $3",
+			wantArgs: []any{"ann@example.com", 10, 0},
+		},
+		{
+			name:     "CreatedBounds",
+			filter:   domain.UserFilter{CreatedAfter: &createdAt, UpdatedBefore: &createdAt},
+			sort:     domain.DefaultUserSort,
+			after:    cursor,
+			wantSQL:  "SELECT " + sqlquery.UserColumns + " FROM users WHERE deleted_at IS NULL AND created_at >= $1 AND updated_at < $2 AND (created_at, id) < ($3, $4) ORDER BY created_at DESC, id DESC LIMIT $5 OFFSET $6",
+			wantArgs: []any{createdAt, createdAt, createdAt, "u1", 10, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := sqlquery.NewUserQuery(numbered, tt.filter)
+			if tt.after != nil {
+				q.After(tt.sort, tt.after)
+			}
+			sql := q.ListSQL(tt.sort, 10, 0)
+			if sql != tt.wantSQL {
+				t.Errorf("ListSQL() = %q, want %q", sql, tt.wantSQL)
+			}
+			if len(q.Args) != len(tt.wantArgs) {
+				t.Fatalf("Args = %v, want %v", q.Args, tt.wantArgs)
+			}
+			for i := range q.Args {
+				if q.Args[i] != tt.wantArgs[i] {
+					t.Errorf("Args[%d] = %v, want %v", i, q.Args[i], tt.wantArgs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUserQuerySelectSQL(t *testing.T) {
+	q := sqlquery.NewUserQuery(numbered, domain.UserFilter{IncludeDeleted: true})
+	if got, want := q.SelectSQL("count(*)"), "SELECT count(*) FROM users"; got != want {
+		t.Errorf("SelectSQL() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec converts values of type T to and from their cached byte form
+type Codec[T any] interface {
+	Marshal(value T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+// JSONCodec encodes values as JSON
+type JSONCodec[T any] struct{}
+
+// Marshal encodes a value as JSON
+func (JSONCodec[T]) Marshal(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Unmarshal decodes a JSON value
+func (JSONCodec[T]) Unmarshal(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// ProtoCodec encodes protobuf messages, such as those in api/grpc, in the
+// protobuf wire format. T must be a pointer to a generated message type. A
+// nil message is encoded like an empty one and decoded as an empty message,
+// so ProtoCodec cannot hold negative cache entries.
+type ProtoCodec[T proto.Message] struct{}
+
+// Marshal encodes a message in the protobuf wire format
+func (ProtoCodec[T]) Marshal(value T) ([]byte, error) {
+	return proto.Marshal(value)
+}
+
+// Unmarshal decodes a message from the protobuf wire format
+func (ProtoCodec[T]) Unmarshal(data []byte) (T, error) {
+	var zero T
+	// Generated messages report their type even through a nil pointer
+	value, ok := zero.ProtoReflect().Type().New().Interface().(T)
+	if !ok {
+		return zero, fmt.Errorf("cache: cannot instantiate %T", zero)
+	}
+	if err := proto.Unmarshal(data, value); err != nil {
+		return zero, err
+	}
+	return value, nil
+}
+
+// BinaryCodec encodes values with encoding/gob, a compact self-describing
+// binary format. It is smaller and faster to decode than JSON for structs
+// but can only be read by Go. Gob cannot encode nil, so a nil pointer, map,
+// slice or interface, such as a negative cache entry, is stored as no bytes
+// and decoded as nil.
+type BinaryCodec[T any] struct{}
+
+// Marshal encodes a value with gob
+func (BinaryCodec[T]) Marshal(value T) ([]byte, error) {
+	if isNil(value) {
+		return []byte{}, nil
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes a gob value
+func (BinaryCodec[T]) Unmarshal(data []byte) (T, error) {
+	var value T
+	if len(data) == 0 {
+		return value, nil
+	}
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value)
+	return value, err
+}
+
+// isNil reports whether value is a nil pointer, map, slice or interface
+func isNil[T any](value T) bool {
+	v := reflect.ValueOf(&value).Elem()
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/alexanderbkl/golang-hexagonal-boilerplate/api/grpc"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCodecsRoundTrip(t *testing.T) {
+	user := &domain.User{
+		ID:        "u1",
+		Email:     "ann@example.com",
+		Name:      "Ann",
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Version:   2,
+	}
+	tests := []struct {
+		name  string
+		codec Codec[*domain.User]
+	}{
+		{"JSON", JSONCodec[*domain.User]{}},
+		{"Binary", BinaryCodec[*domain.User]{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.codec.Marshal(user)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			got, err := tt.codec.Unmarshal(data)
+			if err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if *got != *user {
+				t.Errorf("Unmarshal() = %+v, want %+v", got, user)
+			}
+		})
+
+		t.Run(tt.name+"Nil", func(t *testing.T) {
+			data, err := tt.codec.Marshal(nil)
+			if err != nil {
+				t.Fatalf("Marshal(nil) error = %v", err)
+			}
+			got, err := tt.codec.Unmarshal(data)
+			if err != nil || got != nil {
+				t.Errorf("Unmarshal() of nil = %v, %v, want nil", got, err)
+			}
+		})
+	}
+}
+
+func TestBinaryCodecNilCollections(t *testing.T) {
+	codec := BinaryCodec[map[string]int]{}
+	data, err := codec.Marshal(nil)
+	if err != nil {
+		t.Fatalf("Marshal(nil) error = %v", err)
+	}
+	if got, err := codec.Unmarshal(data); err != nil || got != nil {
+		t.Errorf("Unmarshal() of a nil map = %v, %v, want nil", got, err)
+	}
+
+	data, err = codec.Marshal(map[string]int{"a": 1})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if got, err := codec.Unmarshal(data); err != nil || got["a"] != 1 {
+		t.Errorf("Unmarshal() = %v, %v, want map[a:1]", got, err)
+	}
+}
+
+func TestProtoCodec(t *testing.T) {
+	codec := ProtoCodec[*pb.User]{}
+	user := &pb.User{Id: "u1", Email: "ann@example.com", Name: "Ann", Version: 2}
+
+	data, err := codec.Marshal(user)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	got, err := codec.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !proto.Equal(got, user) {
+		t.Errorf("Unmarshal() = %v, want %v", got, user)
+	}
+
+	// A nil message comes back empty rather than nil
+	data, err = codec.Marshal(nil)
+	if err != nil {
+		t.Fatalf("Marshal(nil) error = %v", err)
+	}
+	if got, err := codec.Unmarshal(data); err != nil || got == nil || !proto.Equal(got, &pb.User{}) {
+		t.Errorf("Unmarshal() of nil = %v, %v, want an empty message", got, err)
+	}
+
+	if _, err := codec.Unmarshal([]byte{0xff}); err == nil {
+		t.Errorf("Unmarshal() of invalid data error = nil, want an error")
+	}
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+// TypedCache stores values of type T in a CacheRepository using a Codec
+type TypedCache[T any] struct {
+	cache ports.CacheRepository
+	codec Codec[T]
+}
+
+// NewTypedCache creates a typed view over a cache repository
+func NewTypedCache[T any](cache ports.CacheRepository, codec Codec[T]) *TypedCache[T] {
+	return &TypedCache[T]{
+		cache: cache,
+		codec: codec,
+	}
+}
+
+// Set stores a value in the cache
+func (c *TypedCache[T]) Set(ctx context.Context, key string, value T, expiration time.Duration) error {
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.cache.Set(ctx, key, data, expiration)
+}
+
+// Get retrieves a value from the cache. It returns ports.ErrCacheMiss when the
+// key is absent.
+func (c *TypedCache[T]) Get(ctx context.Context, key string) (T, error) {
+	var zero T
+
+	data, err := c.cache.Get(ctx, key)
+	if err != nil {
+		return zero, err
+	}
+	return c.codec.Unmarshal(data)
+}
+
+// Delete removes a value from the cache
+func (c *TypedCache[T]) Delete(ctx context.Context, key string) error {
+	return c.cache.Delete(ctx, key)
+}
+
+// GetMany retrieves the values present for keys. Missing keys and values that
+// fail to decode are omitted from the result.
+func (c *TypedCache[T]) GetMany(ctx context.Context, keys []string) (map[string]T, error) {
+	data, err := c.cache.GetMany(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]T, len(data))
+	for key, raw := range data {
+		value, err := c.codec.Unmarshal(raw)
+		if err != nil {
+			continue
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// SetMany stores several values in the cache
+func (c *TypedCache[T]) SetMany(ctx context.Context, values map[string]T, expiration time.Duration) error {
+	data := make(map[string][]byte, len(values))
+	for key, value := range values {
+		raw, err := c.codec.Marshal(value)
+		if err != nil {
+			return err
+		}
+		data[key] = raw
+	}
+	return c.cache.SetMany(ctx, data, expiration)
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/memory"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+func TestTypedCache(t *testing.T) {
+	codecs := map[string]Codec[*domain.User]{
+		"JSON":   JSONCodec[*domain.User]{},
+		"Binary": BinaryCodec[*domain.User]{},
+	}
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			cache := NewTypedCache(memory.NewCacheRepository(10), codec)
+			ann := &domain.User{ID: "u1", Name: "Ann"}
+
+			if err := cache.Set(ctx, "ann", ann, time.Minute); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			if got, err := cache.Get(ctx, "ann"); err != nil || got.Name != "Ann" {
+				t.Errorf("Get() = %v, %v, want Ann", got, err)
+			}
+
+			// Negative entries are stored as nil
+			if err := cache.Set(ctx, "missing", nil, time.Minute); err != nil {
+				t.Fatalf("Set(nil) error = %v", err)
+			}
+			if got, err := cache.Get(ctx, "missing"); err != nil || got != nil {
+				t.Errorf("Get() of a nil value = %v, %v, want nil", got, err)
+			}
+
+			if err := cache.Delete(ctx, "ann"); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if _, err := cache.Get(ctx, "ann"); !errors.Is(err, ports.ErrCacheMiss) {
+				t.Errorf("Get() after Delete() error = %v, want %v", err, ports.ErrCacheMiss)
+			}
+		})
+	}
+}
+
+func TestTypedCacheBatches(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewCacheRepository(10)
+	cache := NewTypedCache(repo, JSONCodec[*domain.User]{})
+
+	values := map[string]*domain.User{
+		"ann": {ID: "u1", Name: "Ann"},
+		"ben": {ID: "u2", Name: "Ben"},
+	}
+	if err := cache.SetMany(ctx, values, time.Minute); err != nil {
+		t.Fatalf("SetMany() error = %v", err)
+	}
+	// Values that fail to decode are skipped
+	if err := repo.Set(ctx, "corrupt", []byte("{"), time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	got, err := cache.GetMany(ctx, []string{"ann", "ben", "corrupt", "missing"})
+	if err != nil {
+		t.Fatalf("GetMany() error = %v", err)
+	}
+	if len(got) != 2 || got["ann"].Name != "Ann" || got["ben"].Name != "Ben" {
+		t.Errorf("GetMany() = %v, want ann and ben", got)
+	}
+}
+
+// failingCodec fails to encode every value
+type failingCodec struct{ JSONCodec[*domain.User] }
+
+var errEncode = errors.New("cannot encode")
+
+func (failingCodec) Marshal(value *domain.User) ([]byte, error) {
+	return nil, errEncode
+}
+
+func TestTypedCacheEncodeErrors(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewCacheRepository(10)
+	cache := NewTypedCache[*domain.User](repo, failingCodec{})
+
+	if err := cache.Set(ctx, "ann", &domain.User{}, time.Minute); !errors.Is(err, errEncode) {
+		t.Errorf("Set() error = %v, want %v", err, errEncode)
+	}
+	err := cache.SetMany(ctx, map[string]*domain.User{"ann": {}}, time.Minute)
+	if !errors.Is(err, errEncode) {
+		t.Errorf("SetMany() error = %v, want %v", err, errEncode)
+	}
+	if ok, _ := repo.Exists(ctx, "ann"); ok {
+		t.Errorf("a value that failed to encode was stored")
+	}
+}
@@ -0,0 +1,33 @@
+package domain
+
+// MaxBulkCreateUsers caps the number of users created by one bulk create
+const MaxBulkCreateUsers = 1000
+
+// MaxImportUsers caps the number of users created by one import, which holds
+// them in memory and creates them in a single unit of work
+const MaxImportUsers = 50 * MaxBulkCreateUsers
+
+// BulkCreateStatus is the outcome of one user in a bulk create
+type BulkCreateStatus string
+
+// Bulk create outcomes
+const (
+	// BulkCreateCreated means the user was created
+	BulkCreateCreated BulkCreateStatus = "CREATED"
+	// BulkCreateDuplicate means the email was already taken, possibly by an
+	// earlier user in the same batch
+	BulkCreateDuplicate BulkCreateStatus = "DUPLICATE"
+	// BulkCreateInvalid means the input failed validation
+	BulkCreateInvalid BulkCreateStatus = "INVALID"
+)
+
+// BulkCreateResult reports what happened to one user of a bulk create
+type BulkCreateResult struct {
+	// Index is the position of the user in the input
+	Index  int              `json:"index"`
+	Status BulkCreateStatus `json:"status"`
+	// User is set when the user was created
+	User *User `json:"user,omitempty"`
+	// Error explains why the user was not created
+	Error string `json:"error,omitempty"`
+}
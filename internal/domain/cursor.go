@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+// UserCursor marks a position in a sorted user listing. Listings that resume
+// from a cursor return the users strictly after it. Only the field the
+// listing is sorted by is set, besides the ID.
+type UserCursor struct {
+	Sort      UserSort  `json:"s"`
+	CreatedAt time.Time `json:"t,omitzero"`
+	Name      string    `json:"n,omitempty"`
+	Email     string    `json:"e,omitempty"`
+	ID        string    `json:"id"`
+}
+
+// NewUserCursor returns the cursor positioned at user in a listing sorted
+// by sort
+func NewUserCursor(user *User, sort UserSort) UserCursor {
+	c := UserCursor{Sort: sort, ID: user.ID}
+	switch sort.Field {
+	case UserSortCreatedAt:
+		c.CreatedAt = user.CreatedAt
+	case UserSortName:
+		c.Name = user.Name
+	case UserSortEmail:
+		c.Email = user.Email
+	}
+	return c
+}
+
+// Encode returns the cursor as an opaque, URL-safe token
+func (c UserCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeUserCursor parses a token produced by UserCursor.Encode
+func DecodeUserCursor(token string) (UserCursor, error) {
+	var c UserCursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return UserCursor{}, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" || c.Sort.Validate() != nil {
+		return UserCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// UserPage is one page of a cursor-paginated user listing
+type UserPage struct {
+	Users []*User
+	// Sort is the order the page was listed in, which cursors into the page
+	// must be made for
+	Sort UserSort
+	// NextCursor resumes the listing after the last user. It is empty on
+	// the last page.
+	NextCursor string
+}
+
+// HasNextPage reports whether more users follow this page
+func (p *UserPage) HasNextPage() bool {
+	return p.NextCursor != ""
+}
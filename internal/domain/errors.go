@@ -4,10 +4,50 @@ import (
 	"errors"
 )
 
+// ErrorCategory classifies domain errors so that each transport can map them
+// to its own status codes
+type ErrorCategory string
+
+// Error categories
+const (
+	CategoryInternal        ErrorCategory = "INTERNAL"
+	CategoryNotFound        ErrorCategory = "NOT_FOUND"
+	CategoryInvalid         ErrorCategory = "INVALID"
+	CategoryConflict        ErrorCategory = "CONFLICT"
+	CategoryUnauthenticated ErrorCategory = "UNAUTHENTICATED"
+	CategoryForbidden       ErrorCategory = "FORBIDDEN"
+)
+
+// Error is an error the domain reports to clients. Its message, and that of
+// any error wrapping it, must be safe to show to them.
+type Error struct {
+	Category ErrorCategory
+	// Reason is a stable UPPER_SNAKE_CASE identifier clients can match on
+	Reason  string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
 // Domain errors
 var (
-	ErrUserNotFound      = errors.New("user not found")
-	ErrUserAlreadyExists = errors.New("user already exists")
-	ErrInvalidInput      = errors.New("invalid input")
-	ErrInternalServer    = errors.New("internal server error")
+	ErrUserNotFound      = &Error{Category: CategoryNotFound, Reason: "USER_NOT_FOUND", Message: "user not found"}
+	ErrUserAlreadyExists = &Error{Category: CategoryConflict, Reason: "USER_ALREADY_EXISTS", Message: "user already exists"}
+	ErrInvalidInput      = &Error{Category: CategoryInvalid, Reason: "INVALID_INPUT", Message: "invalid input"}
+	ErrConflict          = &Error{Category: CategoryConflict, Reason: "VERSION_CONFLICT", Message: "user was modified concurrently"}
+	ErrInvalidCursor     = &Error{Category: CategoryInvalid, Reason: "INVALID_CURSOR", Message: "invalid cursor"}
+	ErrCursorExpired     = &Error{Category: CategoryInvalid, Reason: "CURSOR_EXPIRED", Message: "cursor expired; events after it are no longer retained"}
+	ErrInternalServer    = &Error{Category: CategoryInternal, Reason: "INTERNAL", Message: "internal server error"}
 )
+
+// AsError returns the domain error in err's chain. Errors without one, and
+// internal domain errors, are reported as ErrInternalServer with ok unset, as
+// their messages may expose implementation details.
+func AsError(err error) (domainErr *Error, ok bool) {
+	if errors.As(err, &domainErr) && domainErr.Category != CategoryInternal {
+		return domainErr, true
+	}
+	return ErrInternalServer, false
+}
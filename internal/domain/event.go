@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// UserEventType is the kind of change a UserEvent reports
+type UserEventType string
+
+// User event types
+const (
+	UserCreated UserEventType = "CREATED"
+	UserUpdated UserEventType = "UPDATED"
+	// UserDeleted reports a soft delete
+	UserDeleted  UserEventType = "DELETED"
+	UserRestored UserEventType = "RESTORED"
+	// UserPurged reports a permanent removal
+	UserPurged UserEventType = "PURGED"
+)
+
+// UserEvent reports a change to a user
+type UserEvent struct {
+	// ID is assigned by the event bus when the event is published. Events
+	// are delivered in ID order, and an ID is the cursor to resume watching
+	// after its event.
+	ID     string        `json:"id"`
+	Type   UserEventType `json:"type"`
+	UserID string        `json:"user_id"`
+	// User is the user after the change; nil for deletes and purges
+	User *User     `json:"user,omitempty"`
+	Time time.Time `json:"time"`
+}
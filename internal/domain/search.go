@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/trigram"
+)
+
+// UserSearchResult is a user matched by a search, with its relevance score.
+// Scores are only comparable within the results of one search.
+type UserSearchResult struct {
+	User  *User   `json:"user"`
+	Score float64 `json:"score"`
+}
+
+// wordMatchBonus is added to the score of users whose name and email contain
+// every word of the query
+const wordMatchBonus = 0.1
+
+// MatchUser scores user against a search query for adapters without native
+// search. A user matches when its name or email is trigram-similar to the
+// query or contains every query word, approximating the PostgreSQL adapter.
+func MatchUser(user *User, query string) (float64, bool) {
+	score := max(trigram.Similarity(user.Name, query), trigram.Similarity(user.Email, query))
+	matched := score >= trigram.DefaultThreshold
+
+	words := make(map[string]bool)
+	for _, w := range trigram.Words(user.Name + " " + user.Email) {
+		words[w] = true
+	}
+	queryWords := trigram.Words(query)
+	allWords := len(queryWords) > 0
+	for _, w := range queryWords {
+		allWords = allWords && words[w]
+	}
+	if allWords {
+		score += wordMatchBonus
+		matched = true
+	}
+
+	return score, matched
+}
+
+// RankUsers returns the users matching query, best match first, keeping at
+// most limit results
+func RankUsers(users []*User, query string, limit int) []*UserSearchResult {
+	results := make([]*UserSearchResult, 0)
+	for _, user := range users {
+		if score, ok := MatchUser(user, query); ok {
+			results = append(results, &UserSearchResult{User: user, Score: score})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return strings.Compare(results[i].User.ID, results[j].User.ID) < 0
+	})
+	if limit >= 0 && limit < len(results) {
+		results = results[:limit]
+	}
+
+	return results
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 )
 
@@ -11,6 +12,100 @@ type User struct {
 	Name      string    `json:"name"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// Version starts at 1 and is incremented by every update
+	Version int64 `json:"version"`
+	// DeletedAt is set while the user is soft-deleted
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// IsDeleted reports whether the user has been soft-deleted
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// UserFilter narrows the users returned by a listing. Zero fields match
+// every user.
+type UserFilter struct {
+	// IncludeDeleted also returns soft-deleted users
+	IncludeDeleted bool
+	// EmailPrefix matches emails starting with it, ignoring case
+	EmailPrefix string
+	// NameContains matches names containing it, ignoring case
+	NameContains string
+	// CreatedAfter and CreatedBefore bound created_at to the half-open
+	// range [CreatedAfter, CreatedBefore)
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// UpdatedAfter and UpdatedBefore bound updated_at the same way
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+}
+
+// Validate checks that the time ranges are not inverted
+func (f UserFilter) Validate() error {
+	if f.CreatedAfter != nil && f.CreatedBefore != nil && f.CreatedAfter.After(*f.CreatedBefore) {
+		return ErrInvalidInput
+	}
+	if f.UpdatedAfter != nil && f.UpdatedBefore != nil && f.UpdatedAfter.After(*f.UpdatedBefore) {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+// UserSortField is a field users can be sorted by
+type UserSortField string
+
+// Sortable user fields
+const (
+	UserSortCreatedAt UserSortField = "created_at"
+	UserSortName      UserSortField = "name"
+	UserSortEmail     UserSortField = "email"
+)
+
+// UserSort orders a user listing. Users with equal sort values are ordered
+// by ID in the same direction, so the order is total.
+type UserSort struct {
+	Field      UserSortField `json:"f"`
+	Descending bool          `json:"d,omitempty"`
+}
+
+// DefaultUserSort lists the newest users first
+var DefaultUserSort = UserSort{Field: UserSortCreatedAt, Descending: true}
+
+// Validate checks that the sort field is one of the sortable fields
+func (s UserSort) Validate() error {
+	switch s.Field {
+	case UserSortCreatedAt, UserSortName, UserSortEmail:
+		return nil
+	}
+	return ErrInvalidInput
+}
+
+// ParseUserSort parses an order like "name" or "created_at desc". Orders
+// are ascending unless followed by "desc"; an empty order is DefaultUserSort.
+func ParseUserSort(order string) (UserSort, error) {
+	parts := strings.Fields(order)
+	if len(parts) == 0 {
+		return DefaultUserSort, nil
+	}
+	if len(parts) > 2 {
+		return UserSort{}, ErrInvalidInput
+	}
+
+	sort := UserSort{Field: UserSortField(parts[0])}
+	if len(parts) == 2 {
+		switch strings.ToLower(parts[1]) {
+		case "asc":
+		case "desc":
+			sort.Descending = true
+		default:
+			return UserSort{}, ErrInvalidInput
+		}
+	}
+	if err := sort.Validate(); err != nil {
+		return UserSort{}, err
+	}
+	return sort, nil
 }
 
 // CreateUserInput represents the input for creating a user
@@ -19,8 +114,54 @@ type CreateUserInput struct {
 	Name  string `json:"name"`
 }
 
+// Normalize returns a copy of the input with its email and name normalized
+func (i CreateUserInput) Normalize() CreateUserInput {
+	return CreateUserInput{
+		Email: NormalizeEmail(i.Email),
+		Name:  NormalizeName(i.Name),
+	}
+}
+
+// Validate checks the email and name, returning a *ValidationError listing
+// every invalid field. Normalize the input first.
+func (i *CreateUserInput) Validate() error {
+	var v validator
+	v.email("email", i.Email)
+	v.name("name", i.Name)
+	return v.err()
+}
+
 // UpdateUserInput represents the input for updating a user
 type UpdateUserInput struct {
 	Email *string `json:"email,omitempty"`
 	Name  *string `json:"name,omitempty"`
+	// ExpectedVersion, when set, makes the update fail with ErrConflict
+	// unless it matches the stored version
+	ExpectedVersion *int64 `json:"expected_version,omitempty"`
+}
+
+// Normalize returns a copy of the input with the fields it sets normalized
+func (i UpdateUserInput) Normalize() UpdateUserInput {
+	if i.Email != nil {
+		email := NormalizeEmail(*i.Email)
+		i.Email = &email
+	}
+	if i.Name != nil {
+		name := NormalizeName(*i.Name)
+		i.Name = &name
+	}
+	return i
+}
+
+// Validate checks the fields the input sets, returning a *ValidationError
+// listing every invalid field. Normalize the input first.
+func (i *UpdateUserInput) Validate() error {
+	var v validator
+	if i.Email != nil {
+		v.email("email", *i.Email)
+	}
+	if i.Name != nil {
+		v.name("name", *i.Name)
+	}
+	return v.err()
 }
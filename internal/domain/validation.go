@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Length limits matching the VARCHAR(255) columns of the users table
+const (
+	MaxEmailLength = 255
+	MaxNameLength  = 255
+)
+
+// FieldViolation describes why one field of an input is invalid
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// ValidationError lists every invalid field of an input. It wraps
+// ErrInvalidInput, so errors.Is(err, ErrInvalidInput) holds.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.Field + " " + v.Description
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidInput.Message, strings.Join(parts, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidInput
+}
+
+// validator collects the violations of one input
+type validator struct {
+	violations []FieldViolation
+}
+
+func (v *validator) add(field, description string) {
+	v.violations = append(v.violations, FieldViolation{Field: field, Description: description})
+}
+
+// err returns a ValidationError if any violation was found
+func (v *validator) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v.violations}
+}
+
+// ValidateOffsetPage checks the limit and offset of an offset-paginated
+// listing
+func ValidateOffsetPage(limit, offset int) error {
+	var v validator
+	if limit < 0 {
+		v.add("limit", "must not be negative")
+	}
+	if offset < 0 {
+		v.add("offset", "must not be negative")
+	}
+	return v.err()
+}
+
+// ValidateBulkSize checks that a list of n users to create holds at most max
+// users
+func ValidateBulkSize(n, max int) error {
+	var v validator
+	if n > max {
+		v.add("input", fmt.Sprintf("must hold at most %d users", max))
+	}
+	return v.err()
+}
+
+// email checks that email is a bare RFC 5322 address, such as
+// "ann@example.com" but not "Ann <ann@example.com>"
+func (v *validator) email(field, email string) {
+	switch {
+	case email == "":
+		v.add(field, "is required")
+	case utf8.RuneCountInString(email) > MaxEmailLength:
+		v.add(field, fmt.Sprintf("must be at most %d characters", MaxEmailLength))
+	default:
+		addr, err := mail.ParseAddress(email)
+		if err != nil || addr.Name != "" || addr.Address != email {
+			v.add(field, "must be a valid email address")
+		}
+	}
+}
+
+// name checks that name is valid UTF-8 text without control characters and
+// with at least one letter
+func (v *validator) name(field, name string) {
+	switch {
+	case name == "":
+		v.add(field, "is required")
+	case !utf8.ValidString(name):
+		v.add(field, "must be valid UTF-8")
+	case utf8.RuneCountInString(name) > MaxNameLength:
+		v.add(field, fmt.Sprintf("must be at most %d characters", MaxNameLength))
+	case strings.ContainsFunc(name, unicode.IsControl):
+		v.add(field, "must not contain control characters")
+	case !strings.ContainsFunc(name, unicode.IsLetter):
+		v.add(field, "must contain a letter")
+	}
+}
+
+// NormalizeEmail trims surrounding whitespace and lowercases email
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizeName trims surrounding whitespace and converts name to Unicode
+// NFC, so that visually identical names are stored identically
+func NormalizeName(name string) string {
+	return norm.NFC.String(strings.TrimSpace(name))
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCreateUserInputValidate(t *testing.T) {
+	long := strings.Repeat("a", MaxEmailLength)
+	tests := []struct {
+		name  string
+		input CreateUserInput
+		want  []FieldViolation
+	}{
+		{"Valid", CreateUserInput{Email: "ann@example.com", Name: "Ann"}, nil},
+		{"ValidUnicodeName", CreateUserInput{Email: "zoe@example.com", Name: "Zoë O'Brien-Łukasz"}, nil},
+		{"Missing", CreateUserInput{}, []FieldViolation{
+			{"email", "is required"},
+			{"name", "is required"},
+		}},
+		{"BadEmail", CreateUserInput{Email: "not-an-email", Name: "Ann"}, []FieldViolation{
+			{"email", "must be a valid email address"},
+		}},
+		{"DisplayNameEmail", CreateUserInput{Email: "Ann <ann@example.com>", Name: "Ann"}, []FieldViolation{
+			{"email", "must be a valid email address"},
+		}},
+		{"LongEmail", CreateUserInput{Email: long + "@example.com", Name: "Ann"}, []FieldViolation{
+			{"email", "must be at most 255 characters"},
+		}},
+		{"LongName", CreateUserInput{Email: "ann@example.com", Name: strings.Repeat("é", MaxNameLength+1)}, []FieldViolation{
+			{"name", "must be at most 255 characters"},
+		}},
+		{"ControlCharacters", CreateUserInput{Email: "ann@example.com", Name: "Ann\x00"}, []FieldViolation{
+			{"name", "must not contain control characters"},
+		}},
+		{"NoLetters", CreateUserInput{Email: "ann@example.com", Name: "1234"}, []FieldViolation{
+			{"name", "must contain a letter"},
+		}},
+		{"InvalidUTF8", CreateUserInput{Email: "ann@example.com", Name: "Ann\xff"}, []FieldViolation{
+			{"name", "must be valid UTF-8"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() error = %v, want a *ValidationError", err)
+			}
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("Validate() error does not wrap ErrInvalidInput")
+			}
+			if !slices.Equal(validationErr.Violations, tt.want) {
+				t.Errorf("Validate() violations = %v, want %v", validationErr.Violations, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateUserInputValidate(t *testing.T) {
+	if err := (&UpdateUserInput{}).Validate(); err != nil {
+		t.Errorf("Validate() of an empty update error = %v, want nil", err)
+	}
+
+	email := ""
+	err := (&UpdateUserInput{Email: &email}).Validate()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Violations[0].Field != "email" {
+		t.Errorf("Validate() with a blank email error = %v, want an email violation", err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	email, name := "  Ann@Example.COM ", " Zoe\u0308 "
+	got := UpdateUserInput{Email: &email, Name: &name}.Normalize()
+	if *got.Email != "ann@example.com" {
+		t.Errorf("Normalize() email = %q, want %q", *got.Email, "ann@example.com")
+	}
+	if *got.Name != "Zo\u00eb" {
+		t.Errorf("Normalize() name = %q, want NFC %q", *got.Name, "Zo\u00eb")
+	}
+	if email != "  Ann@Example.COM " {
+		t.Errorf("Normalize() modified its input")
+	}
+}
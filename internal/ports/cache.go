@@ -2,13 +2,22 @@ package ports
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
-// CacheRepository defines the interface for cache operations
+// ErrCacheMiss is returned when a key is not present in the cache
+var ErrCacheMiss = errors.New("cache miss")
+
+// CacheRepository defines the interface for cache operations. Values are
+// opaque byte slices; use cache.TypedCache to store typed values.
 type CacheRepository interface {
-	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
-	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value []byte, expiration time.Duration) error
+	Get(ctx context.Context, key string) ([]byte, error)
 	Delete(ctx context.Context, key string) error
 	Exists(ctx context.Context, key string) (bool, error)
+	// GetMany returns the values for the keys that are present; missing keys
+	// are omitted from the result
+	GetMany(ctx context.Context, keys []string) (map[string][]byte, error)
+	SetMany(ctx context.Context, values map[string][]byte, expiration time.Duration) error
 }
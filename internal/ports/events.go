@@ -0,0 +1,30 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+)
+
+// UserEventBus distributes user change events to watchers. It retains a
+// bounded history of events so that watchers can resume after a disconnect.
+type UserEventBus interface {
+	// Publish assigns each event its ID and delivers it to subscribers, in
+	// order
+	Publish(ctx context.Context, events ...*domain.UserEvent) error
+	// Subscribe returns a stream of the events published after the event
+	// with ID after, or of the events published from now on if after is
+	// empty. It returns domain.ErrInvalidCursor for a malformed ID and
+	// domain.ErrCursorExpired if events after it are no longer retained.
+	Subscribe(ctx context.Context, after string) (UserEventStream, error)
+}
+
+// UserEventStream is a subscription to a UserEventBus
+type UserEventStream interface {
+	// Next blocks until the next event is published or ctx is done. It
+	// returns domain.ErrCursorExpired if the stream fell so far behind that
+	// the next event is no longer retained.
+	Next(ctx context.Context) (*domain.UserEvent, error)
+	// Close releases the subscription
+	Close() error
+}
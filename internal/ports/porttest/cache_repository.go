@@ -0,0 +1,115 @@
+package porttest
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+// CacheRepositoryFactory returns an empty cache
+type CacheRepositoryFactory func(t *testing.T) ports.CacheRepository
+
+// RunCacheRepositoryTests runs the ports.CacheRepository conformance suite
+func RunCacheRepositoryTests(t *testing.T, newCache CacheRepositoryFactory) {
+	t.Run("SetGet", func(t *testing.T) {
+		ctx := context.Background()
+		cache := newCache(t)
+		mustSet(t, cache, "a", "1")
+
+		got, err := cache.Get(ctx, "a")
+		if err != nil || string(got) != "1" {
+			t.Errorf("Get() = %q, %v, want %q", got, err, "1")
+		}
+
+		mustSet(t, cache, "a", "2")
+		if got, _ := cache.Get(ctx, "a"); string(got) != "2" {
+			t.Errorf("Get() after overwrite = %q, want %q", got, "2")
+		}
+	})
+
+	t.Run("Miss", func(t *testing.T) {
+		cache := newCache(t)
+		if _, err := cache.Get(context.Background(), "missing"); !errors.Is(err, ports.ErrCacheMiss) {
+			t.Errorf("Get() of a missing key error = %v, want %v", err, ports.ErrCacheMiss)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		ctx := context.Background()
+		cache := newCache(t)
+		mustSet(t, cache, "a", "1")
+
+		if err := cache.Delete(ctx, "a"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if _, err := cache.Get(ctx, "a"); !errors.Is(err, ports.ErrCacheMiss) {
+			t.Errorf("Get() after Delete() error = %v, want %v", err, ports.ErrCacheMiss)
+		}
+		if err := cache.Delete(ctx, "a"); err != nil {
+			t.Errorf("Delete() of a missing key error = %v", err)
+		}
+	})
+
+	t.Run("Exists", func(t *testing.T) {
+		ctx := context.Background()
+		cache := newCache(t)
+		mustSet(t, cache, "a", "1")
+
+		for key, want := range map[string]bool{"a": true, "b": false} {
+			if got, err := cache.Exists(ctx, key); err != nil || got != want {
+				t.Errorf("Exists(%q) = %t, %v, want %t", key, got, err, want)
+			}
+		}
+	})
+
+	t.Run("GetManySetMany", func(t *testing.T) {
+		ctx := context.Background()
+		cache := newCache(t)
+		values := map[string][]byte{"a": []byte("1"), "b": []byte("2")}
+		if err := cache.SetMany(ctx, values, time.Minute); err != nil {
+			t.Fatalf("SetMany() error = %v", err)
+		}
+
+		got, err := cache.GetMany(ctx, []string{"a", "b", "missing"})
+		if err != nil {
+			t.Fatalf("GetMany() error = %v", err)
+		}
+		if !maps.EqualFunc(got, values, func(a, b []byte) bool { return string(a) == string(b) }) {
+			t.Errorf("GetMany() = %q, want %q", got, values)
+		}
+
+		if got, err := cache.GetMany(ctx, nil); err != nil || len(got) != 0 {
+			t.Errorf("GetMany() of no keys = %q, %v, want none", got, err)
+		}
+	})
+
+	t.Run("ValuesAreCopied", func(t *testing.T) {
+		ctx := context.Background()
+		cache := newCache(t)
+		value := []byte("1")
+		if err := cache.Set(ctx, "a", value, time.Minute); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		value[0] = 'x'
+
+		got, _ := cache.Get(ctx, "a")
+		if string(got) != "1" {
+			t.Fatalf("Get() after changing the stored slice = %q, want %q", got, "1")
+		}
+		got[0] = 'y'
+		if again, _ := cache.Get(ctx, "a"); string(again) != "1" {
+			t.Errorf("Get() after changing a returned slice = %q, want %q", again, "1")
+		}
+	})
+}
+
+func mustSet(t *testing.T, cache ports.CacheRepository, key, value string) {
+	t.Helper()
+	if err := cache.Set(context.Background(), key, []byte(value), time.Minute); err != nil {
+		t.Fatalf("Set(%q) error = %v", key, err)
+	}
+}
@@ -0,0 +1,132 @@
+package porttest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+// UnitOfWorkFactory returns an empty repository and a unit of work whose
+// transactions the repository takes part in
+type UnitOfWorkFactory func(t *testing.T) (ports.UserRepository, ports.UnitOfWork)
+
+// RunUnitOfWorkTests runs the ports.UnitOfWork conformance suite
+func RunUnitOfWorkTests(t *testing.T, newUoW UnitOfWorkFactory) {
+	t.Run("Commit", func(t *testing.T) {
+		ctx := context.Background()
+		repo, uow := newUoW(t)
+
+		err := uow.WithinTx(ctx, func(ctx context.Context) error {
+			return repo.Create(ctx, newUser("1", "alice@example.com", "Alice", baseTime))
+		})
+		if err != nil {
+			t.Fatalf("WithinTx() error = %v", err)
+		}
+
+		if _, err := repo.GetByID(ctx, "1"); err != nil {
+			t.Fatalf("GetByID() after commit error = %v", err)
+		}
+	})
+
+	t.Run("RollbackOnError", func(t *testing.T) {
+		ctx := context.Background()
+		repo, uow := newUoW(t)
+		mustCreate(t, repo, newUser("1", "alice@example.com", "Alice", baseTime))
+		errAbort := errors.New("abort")
+
+		err := uow.WithinTx(ctx, func(ctx context.Context) error {
+			if err := repo.Create(ctx, newUser("2", "bob@example.com", "Bob", baseTime)); err != nil {
+				return err
+			}
+			name := "Alice Smith"
+			if _, err := repo.Update(ctx, "1", &domain.UpdateUserInput{Name: &name}); err != nil {
+				return err
+			}
+			if err := repo.Delete(ctx, "1"); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("WithinTx() error = %v, want %v", err, errAbort)
+		}
+
+		if _, err := repo.GetByID(ctx, "2"); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("GetByID() of rolled back create error = %v, want %v", err, domain.ErrUserNotFound)
+		}
+		got, err := repo.GetByID(ctx, "1")
+		if err != nil {
+			t.Fatalf("GetByID() of rolled back delete error = %v", err)
+		}
+		if got.Name != "Alice" {
+			t.Errorf("name after rollback = %q, want %q", got.Name, "Alice")
+		}
+	})
+
+	t.Run("RollbackKeepsConcurrentWrites", func(t *testing.T) {
+		ctx := context.Background()
+		repo, uow := newUoW(t)
+		mustCreate(t, repo, newUser("1", "alice@example.com", "Alice", baseTime))
+		errAbort := errors.New("abort")
+
+		concurrent := make(chan error, 1)
+		err := uow.WithinTx(ctx, func(txCtx context.Context) error {
+			if err := repo.Create(txCtx, newUser("2", "bob@example.com", "Bob", baseTime)); err != nil {
+				return err
+			}
+			go func() {
+				concurrent <- repo.Create(ctx, newUser("3", "carol@example.com", "Carol", baseTime))
+			}()
+			// Adapters that serialise units of work make the write wait,
+			// others let it finish first
+			select {
+			case err := <-concurrent:
+				concurrent <- err
+			case <-time.After(100 * time.Millisecond):
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("WithinTx() error = %v, want %v", err, errAbort)
+		}
+		if err := <-concurrent; err != nil {
+			t.Fatalf("concurrent Create() error = %v", err)
+		}
+
+		if _, err := repo.GetByID(ctx, "2"); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("GetByID() of rolled back create error = %v, want %v", err, domain.ErrUserNotFound)
+		}
+		for _, id := range []string{"1", "3"} {
+			if _, err := repo.GetByID(ctx, id); err != nil {
+				t.Errorf("GetByID(%q) after rollback error = %v", id, err)
+			}
+		}
+	})
+
+	t.Run("NestedJoinsOuter", func(t *testing.T) {
+		ctx := context.Background()
+		repo, uow := newUoW(t)
+		errAbort := errors.New("abort")
+
+		err := uow.WithinTx(ctx, func(ctx context.Context) error {
+			err := uow.WithinTx(ctx, func(ctx context.Context) error {
+				return repo.Create(ctx, newUser("1", "alice@example.com", "Alice", baseTime))
+			})
+			if err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("WithinTx() error = %v, want %v", err, errAbort)
+		}
+
+		if _, err := repo.GetByID(ctx, "1"); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("GetByID() after outer rollback error = %v, want %v", err, domain.ErrUserNotFound)
+		}
+	})
+}
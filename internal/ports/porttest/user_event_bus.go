@@ -0,0 +1,177 @@
+package porttest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+// UserEventBusFactory returns an empty event bus retaining about history
+// events
+type UserEventBusFactory func(t *testing.T, history int) ports.UserEventBus
+
+// RunUserEventBusTests runs the ports.UserEventBus conformance suite
+func RunUserEventBusTests(t *testing.T, newBus UserEventBusFactory) {
+	t.Run("SubscribeFromNow", func(t *testing.T) {
+		bus := newBus(t, 100)
+		mustPublish(t, bus, newEvent(domain.UserCreated, "0"))
+
+		stream := mustSubscribe(t, bus, "")
+		user := newUser("1", "alice@example.com", "Alice", baseTime)
+		want := []*domain.UserEvent{
+			{Type: domain.UserCreated, UserID: "1", User: user, Time: baseTime},
+			newEvent(domain.UserDeleted, "1"),
+		}
+		mustPublish(t, bus, want...)
+
+		for i, w := range want {
+			got := mustNext(t, stream)
+			if got.ID != w.ID || got.Type != w.Type || got.UserID != w.UserID || !got.Time.Equal(w.Time) {
+				t.Errorf("event %d = %+v, want %+v", i, got, w)
+			}
+			if (got.User == nil) != (w.User == nil) {
+				t.Errorf("event %d user = %v, want %v", i, got.User, w.User)
+			} else if got.User != nil {
+				assertUser(t, got.User, w.User)
+			}
+		}
+	})
+
+	t.Run("PublishAssignsIDs", func(t *testing.T) {
+		bus := newBus(t, 100)
+		events := []*domain.UserEvent{newEvent(domain.UserCreated, "1"), newEvent(domain.UserUpdated, "1")}
+		mustPublish(t, bus, events...)
+
+		if events[0].ID == "" || events[1].ID == "" || events[0].ID == events[1].ID {
+			t.Errorf("Publish() assigned IDs %q and %q, want distinct non-empty IDs", events[0].ID, events[1].ID)
+		}
+	})
+
+	t.Run("ResumeAfterCursor", func(t *testing.T) {
+		bus := newBus(t, 100)
+		events := []*domain.UserEvent{
+			newEvent(domain.UserCreated, "1"),
+			newEvent(domain.UserUpdated, "1"),
+			newEvent(domain.UserPurged, "1"),
+		}
+		mustPublish(t, bus, events...)
+
+		stream := mustSubscribe(t, bus, events[0].ID)
+		for _, want := range events[1:] {
+			if got := mustNext(t, stream); got.ID != want.ID || got.Type != want.Type {
+				t.Errorf("Next() = %s %s, want %s %s", got.ID, got.Type, want.ID, want.Type)
+			}
+		}
+	})
+
+	t.Run("MultipleSubscribers", func(t *testing.T) {
+		bus := newBus(t, 100)
+		first := mustSubscribe(t, bus, "")
+		second := mustSubscribe(t, bus, "")
+		event := newEvent(domain.UserRestored, "1")
+		mustPublish(t, bus, event)
+
+		for _, stream := range []ports.UserEventStream{first, second} {
+			if got := mustNext(t, stream); got.ID != event.ID {
+				t.Errorf("Next() ID = %q, want %q", got.ID, event.ID)
+			}
+		}
+	})
+
+	t.Run("NextWaitsForContext", func(t *testing.T) {
+		bus := newBus(t, 100)
+		stream := mustSubscribe(t, bus, "")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if _, err := stream.Next(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Next() without events error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		bus := newBus(t, 100)
+		if _, err := bus.Subscribe(context.Background(), "not-a-cursor"); !errors.Is(err, domain.ErrInvalidCursor) {
+			t.Errorf("Subscribe() with a malformed cursor error = %v, want %v", err, domain.ErrInvalidCursor)
+		}
+	})
+
+	t.Run("ExpiredCursor", func(t *testing.T) {
+		bus := newBus(t, 3)
+		oldest := newEvent(domain.UserCreated, "0")
+		mustPublish(t, bus, oldest)
+		// Enough events for approximate trimming to drop the oldest one
+		events := make([]*domain.UserEvent, 500)
+		for i := range events {
+			events[i] = newEvent(domain.UserUpdated, "0")
+		}
+		mustPublish(t, bus, events...)
+
+		if _, err := bus.Subscribe(context.Background(), oldest.ID); !errors.Is(err, domain.ErrCursorExpired) {
+			t.Errorf("Subscribe() after a trimmed event error = %v, want %v", err, domain.ErrCursorExpired)
+		}
+	})
+
+	t.Run("StreamFallsBehind", func(t *testing.T) {
+		bus := newBus(t, 3)
+		first := newEvent(domain.UserCreated, "0")
+		mustPublish(t, bus, first)
+		stream := mustSubscribe(t, bus, "")
+		mustPublish(t, bus, newEvent(domain.UserUpdated, "0"))
+		if got := mustNext(t, stream); got.Type != domain.UserUpdated {
+			t.Fatalf("Next() = %s, want %s", got.Type, domain.UserUpdated)
+		}
+
+		// Enough events for approximate trimming to drop the ones the
+		// stream has not read yet
+		events := make([]*domain.UserEvent, 500)
+		for i := range events {
+			events[i] = newEvent(domain.UserUpdated, "0")
+		}
+		mustPublish(t, bus, events...)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := stream.Next(ctx); !errors.Is(err, domain.ErrCursorExpired) {
+			t.Errorf("Next() after unread events were trimmed error = %v, want %v", err, domain.ErrCursorExpired)
+		}
+	})
+}
+
+func newEvent(eventType domain.UserEventType, userID string) *domain.UserEvent {
+	return &domain.UserEvent{Type: eventType, UserID: userID, Time: baseTime}
+}
+
+func mustPublish(t *testing.T, bus ports.UserEventBus, events ...*domain.UserEvent) {
+	t.Helper()
+	if err := bus.Publish(context.Background(), events...); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+}
+
+func mustSubscribe(t *testing.T, bus ports.UserEventBus, after string) ports.UserEventStream {
+	t.Helper()
+	stream, err := bus.Subscribe(context.Background(), after)
+	if err != nil {
+		t.Fatalf("Subscribe(%q) error = %v", after, err)
+	}
+	t.Cleanup(func() { stream.Close() })
+	return stream
+}
+
+// mustNext returns the next event of stream, failing if none arrives within
+// a few seconds
+func mustNext(t *testing.T, stream ports.UserEventStream) *domain.UserEvent {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	event, err := stream.Next(ctx)
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	return event
+}
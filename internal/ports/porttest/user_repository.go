@@ -0,0 +1,771 @@
+// Package porttest provides conformance tests that every adapter
+// implementing a port must pass.
+package porttest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+// UserRepositoryFactory returns an empty repository for a single test
+type UserRepositoryFactory func(t *testing.T) ports.UserRepository
+
+// RunUserRepositoryTests runs the ports.UserRepository conformance suite.
+// newRepo is called once per subtest and must return an empty repository.
+func RunUserRepositoryTests(t *testing.T, newRepo UserRepositoryFactory) {
+	t.Run("CreateAndGetByID", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		want := newUser("1", "alice@example.com", "Alice", baseTime)
+
+		mustCreate(t, repo, want)
+
+		got, err := repo.GetByID(ctx, want.ID)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		assertUser(t, got, want)
+	})
+
+	t.Run("CreateDuplicateEmail", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("1", "alice@example.com", "Alice", baseTime))
+
+		err := repo.Create(ctx, newUser("2", "alice@example.com", "Other Alice", baseTime))
+		if !errors.Is(err, domain.ErrUserAlreadyExists) {
+			t.Fatalf("Create() with a duplicate email error = %v, want %v", err, domain.ErrUserAlreadyExists)
+		}
+	})
+
+	t.Run("BulkCreate", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("1", "taken@example.com", "Taken", baseTime))
+
+		statuses, err := repo.BulkCreate(ctx, []*domain.User{
+			newUser("2", "bob@example.com", "Bob", baseTime.Add(time.Minute)),
+			newUser("3", "taken@example.com", "Not Taken", baseTime.Add(2*time.Minute)),
+			newUser("4", "carol@example.com", "Carol", baseTime.Add(3*time.Minute)),
+			newUser("5", "bob@example.com", "Other Bob", baseTime.Add(4*time.Minute)),
+		})
+		if err != nil {
+			t.Fatalf("BulkCreate() error = %v", err)
+		}
+		want := []domain.BulkCreateStatus{
+			domain.BulkCreateCreated,
+			domain.BulkCreateDuplicate,
+			domain.BulkCreateCreated,
+			domain.BulkCreateDuplicate,
+		}
+		if !slices.Equal(statuses, want) {
+			t.Errorf("BulkCreate() = %v, want %v", statuses, want)
+		}
+
+		got, err := repo.GetByID(ctx, "2")
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		assertUser(t, got, newUser("2", "bob@example.com", "Bob", baseTime.Add(time.Minute)))
+
+		users, err := repo.List(ctx, domain.UserFilter{}, domain.DefaultUserSort, 10, 0)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		assertIDs(t, users, "4", "2", "1")
+	})
+
+	t.Run("BulkCreateEmpty", func(t *testing.T) {
+		statuses, err := newRepo(t).BulkCreate(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("BulkCreate() error = %v", err)
+		}
+		if len(statuses) != 0 {
+			t.Errorf("BulkCreate() = %v, want no statuses", statuses)
+		}
+	})
+
+	t.Run("CreateDuplicateID", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("1", "alice@example.com", "Alice", baseTime))
+
+		err := repo.Create(ctx, newUser("1", "bob@example.com", "Bob", baseTime))
+		if !errors.Is(err, domain.ErrUserAlreadyExists) {
+			t.Fatalf("Create() with a duplicate ID error = %v, want %v", err, domain.ErrUserAlreadyExists)
+		}
+	})
+
+	t.Run("GetByIDMissing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetByID(context.Background(), "missing")
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("GetByID() error = %v, want %v", err, domain.ErrUserNotFound)
+		}
+	})
+
+	t.Run("GetByEmail", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		want := newUser("1", "alice@example.com", "Alice", baseTime)
+		mustCreate(t, repo, want)
+
+		got, err := repo.GetByEmail(ctx, want.Email)
+		if err != nil {
+			t.Fatalf("GetByEmail() error = %v", err)
+		}
+		assertUser(t, got, want)
+	})
+
+	t.Run("GetByEmailMissing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetByEmail(context.Background(), "missing@example.com")
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("GetByEmail() error = %v, want %v", err, domain.ErrUserNotFound)
+		}
+	})
+
+	t.Run("TakenEmails", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("1", "alice@example.com", "Alice", baseTime))
+		mustCreate(t, repo, newUser("2", "bob@example.com", "Bob", baseTime))
+		if err := repo.Delete(ctx, "2"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+
+		got, err := repo.TakenEmails(ctx, []string{"bob@example.com", "carol@example.com", "alice@example.com"})
+		if err != nil {
+			t.Fatalf("TakenEmails() error = %v", err)
+		}
+		slices.Sort(got)
+		if want := []string{"alice@example.com", "bob@example.com"}; !slices.Equal(got, want) {
+			t.Errorf("TakenEmails() = %v, want %v", got, want)
+		}
+
+		if got, err := repo.TakenEmails(ctx, nil); err != nil || len(got) != 0 {
+			t.Errorf("TakenEmails() of no emails = %v, %v, want none", got, err)
+		}
+	})
+
+	t.Run("ListOrderingAndPaging", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+
+		// Insert oldest first so that newest-first ordering cannot come from
+		// insertion order
+		for i := 0; i < 5; i++ {
+			u := newUser(
+				fmt.Sprintf("%d", i),
+				fmt.Sprintf("user%d@example.com", i),
+				fmt.Sprintf("User %d", i),
+				baseTime.Add(time.Duration(i)*time.Minute),
+			)
+			mustCreate(t, repo, u)
+		}
+
+		all, err := repo.List(ctx, domain.UserFilter{}, domain.DefaultUserSort, 10, 0)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		assertIDs(t, all, "4", "3", "2", "1", "0")
+
+		page, err := repo.List(ctx, domain.UserFilter{}, domain.DefaultUserSort, 2, 1)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		assertIDs(t, page, "3", "2")
+
+		past, err := repo.List(ctx, domain.UserFilter{}, domain.DefaultUserSort, 2, 10)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		assertIDs(t, past)
+	})
+
+	t.Run("ListAfter", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+
+		// Users 1 to 3 share a created_at, so the ID must break the tie
+		for i := 0; i < 5; i++ {
+			createdAt := baseTime.Add(time.Duration(i) * time.Minute)
+			if i >= 1 && i <= 3 {
+				createdAt = baseTime.Add(time.Minute)
+			}
+			mustCreate(t, repo, newUser(
+				fmt.Sprintf("%d", i),
+				fmt.Sprintf("user%d@example.com", i),
+				fmt.Sprintf("User %d", i),
+				createdAt,
+			))
+		}
+
+		first, err := repo.ListAfter(ctx, domain.UserFilter{}, domain.DefaultUserSort, nil, 2)
+		if err != nil {
+			t.Fatalf("ListAfter() error = %v", err)
+		}
+		assertIDs(t, first, "4", "3")
+
+		cursor := domain.NewUserCursor(first[len(first)-1], domain.DefaultUserSort)
+		second, err := repo.ListAfter(ctx, domain.UserFilter{}, domain.DefaultUserSort, &cursor, 2)
+		if err != nil {
+			t.Fatalf("ListAfter() error = %v", err)
+		}
+		assertIDs(t, second, "2", "1")
+
+		// A user created while paging does not shift the following pages
+		mustCreate(t, repo, newUser("5", "user5@example.com", "User 5", baseTime.Add(time.Hour)))
+
+		cursor = domain.NewUserCursor(second[len(second)-1], domain.DefaultUserSort)
+		last, err := repo.ListAfter(ctx, domain.UserFilter{}, domain.DefaultUserSort, &cursor, 2)
+		if err != nil {
+			t.Fatalf("ListAfter() error = %v", err)
+		}
+		assertIDs(t, last, "0")
+	})
+
+	t.Run("ListAfterIncludeDeleted", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		for i := 0; i < 3; i++ {
+			mustCreate(t, repo, newUser(
+				fmt.Sprintf("%d", i),
+				fmt.Sprintf("user%d@example.com", i),
+				fmt.Sprintf("User %d", i),
+				baseTime.Add(time.Duration(i)*time.Minute),
+			))
+		}
+		if err := repo.Delete(ctx, "1"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+
+		cursor := domain.UserCursor{Sort: domain.DefaultUserSort, CreatedAt: baseTime.Add(2 * time.Minute), ID: "2"}
+		active, err := repo.ListAfter(ctx, domain.UserFilter{}, domain.DefaultUserSort, &cursor, 10)
+		if err != nil {
+			t.Fatalf("ListAfter() error = %v", err)
+		}
+		assertIDs(t, active, "0")
+
+		all, err := repo.ListAfter(ctx, domain.UserFilter{IncludeDeleted: true}, domain.DefaultUserSort, &cursor, 10)
+		if err != nil {
+			t.Fatalf("ListAfter() error = %v", err)
+		}
+		assertIDs(t, all, "1", "0")
+	})
+
+	t.Run("ListFilters", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("1", "alice@example.com", "Alice Smith", baseTime))
+		mustCreate(t, repo, newUser("2", "Alan@example.com", "Alan Jones", baseTime.Add(time.Minute)))
+		mustCreate(t, repo, newUser("3", "bob@example.com", "Bob Smithers", baseTime.Add(2*time.Minute)))
+		mustCreate(t, repo, newUser("4", "a_b@example.com", "100% Carol", baseTime.Add(3*time.Minute)))
+
+		after := baseTime.Add(time.Minute)
+		before := baseTime.Add(3 * time.Minute)
+		tests := []struct {
+			name   string
+			filter domain.UserFilter
+			want   []string
+		}{
+			{"EmailPrefixIgnoresCase", domain.UserFilter{EmailPrefix: "AL"}, []string{"2", "1"}},
+			{"EmailPrefixEscapesWildcards", domain.UserFilter{EmailPrefix: "a_"}, []string{"4"}},
+			{"NameContainsIgnoresCase", domain.UserFilter{NameContains: "smith"}, []string{"3", "1"}},
+			{"NameContainsEscapesWildcards", domain.UserFilter{NameContains: "0%"}, []string{"4"}},
+			{"CreatedRange", domain.UserFilter{CreatedAfter: &after, CreatedBefore: &before}, []string{"3", "2"}},
+			{"UpdatedRange", domain.UserFilter{UpdatedAfter: &before}, []string{"4"}},
+			{"Combined", domain.UserFilter{EmailPrefix: "a", NameContains: "smith"}, []string{"1"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := repo.List(ctx, tt.filter, domain.DefaultUserSort, 10, 0)
+				if err != nil {
+					t.Fatalf("List() error = %v", err)
+				}
+				assertIDs(t, got, tt.want...)
+
+				after, err := repo.ListAfter(ctx, tt.filter, domain.DefaultUserSort, nil, 10)
+				if err != nil {
+					t.Fatalf("ListAfter() error = %v", err)
+				}
+				assertIDs(t, after, tt.want...)
+
+				count, err := repo.Count(ctx, tt.filter)
+				if err != nil {
+					t.Fatalf("Count() error = %v", err)
+				}
+				if count != int64(len(tt.want)) {
+					t.Errorf("Count() = %d, want %d", count, len(tt.want))
+				}
+			})
+		}
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("1", "one@example.com", "One", baseTime))
+		mustCreate(t, repo, newUser("2", "two@example.com", "Two", baseTime.Add(time.Minute)))
+		mustCreate(t, repo, newUser("3", "three@example.com", "Three", baseTime.Add(2*time.Minute)))
+		if err := repo.Delete(ctx, "2"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+
+		count, err := repo.Count(ctx, domain.UserFilter{})
+		if err != nil {
+			t.Fatalf("Count() error = %v", err)
+		}
+		if count != 2 {
+			t.Errorf("Count() = %d, want 2", count)
+		}
+
+		count, err = repo.Count(ctx, domain.UserFilter{IncludeDeleted: true})
+		if err != nil {
+			t.Fatalf("Count() error = %v", err)
+		}
+		if count != 3 {
+			t.Errorf("Count() including deleted = %d, want 3", count)
+		}
+
+		// Estimates depend on the store's statistics, so only sanity-check them
+		estimate, err := repo.EstimateCount(ctx, domain.UserFilter{})
+		if err != nil {
+			t.Fatalf("EstimateCount() error = %v", err)
+		}
+		if estimate < 0 {
+			t.Errorf("EstimateCount() = %d, want >= 0", estimate)
+		}
+	})
+
+	t.Run("ListSorted", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		// Lowercase names and emails keep the order independent of collation
+		mustCreate(t, repo, newUser("1", "carol@example.com", "carol", baseTime))
+		mustCreate(t, repo, newUser("2", "alice@example.com", "bob", baseTime.Add(time.Minute)))
+		mustCreate(t, repo, newUser("3", "bob@example.com", "alice", baseTime.Add(2*time.Minute)))
+		mustCreate(t, repo, newUser("4", "dave@example.com", "bob", baseTime.Add(3*time.Minute)))
+
+		tests := []struct {
+			sort domain.UserSort
+			want []string
+		}{
+			{domain.UserSort{Field: domain.UserSortName}, []string{"3", "2", "4", "1"}},
+			{domain.UserSort{Field: domain.UserSortName, Descending: true}, []string{"1", "4", "2", "3"}},
+			{domain.UserSort{Field: domain.UserSortEmail}, []string{"2", "3", "1", "4"}},
+			{domain.UserSort{Field: domain.UserSortEmail, Descending: true}, []string{"4", "1", "3", "2"}},
+			{domain.UserSort{Field: domain.UserSortCreatedAt}, []string{"1", "2", "3", "4"}},
+			{domain.UserSort{Field: domain.UserSortCreatedAt, Descending: true}, []string{"4", "3", "2", "1"}},
+		}
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s/desc=%t", tt.sort.Field, tt.sort.Descending), func(t *testing.T) {
+				got, err := repo.List(ctx, domain.UserFilter{}, tt.sort, 10, 0)
+				if err != nil {
+					t.Fatalf("List() error = %v", err)
+				}
+				assertIDs(t, got, tt.want...)
+
+				// Paging one user at a time must visit users in the same order
+				var paged []*domain.User
+				var cursor *domain.UserCursor
+				for {
+					page, err := repo.ListAfter(ctx, domain.UserFilter{}, tt.sort, cursor, 1)
+					if err != nil {
+						t.Fatalf("ListAfter() error = %v", err)
+					}
+					if len(page) == 0 {
+						break
+					}
+					paged = append(paged, page...)
+					c := domain.NewUserCursor(page[0], tt.sort)
+					cursor = &c
+					if len(paged) > len(tt.want) {
+						break
+					}
+				}
+				assertIDs(t, paged, tt.want...)
+			})
+		}
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("1", "john.smith@example.com", "John Smith", baseTime))
+		mustCreate(t, repo, newUser("2", "jane.doe@example.com", "Jane Doe", baseTime))
+		mustCreate(t, repo, newUser("3", "jon.smithe@example.com", "Jon Smithe", baseTime))
+		if err := repo.Delete(ctx, "3"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+
+		results, err := repo.Search(ctx, "jon smth", 10)
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(results) != 1 || results[0].User.ID != "1" {
+			t.Fatalf("Search() = %v, want only John Smith", searchIDs(results))
+		}
+		if results[0].Score <= 0 {
+			t.Errorf("Search() score = %v, want positive", results[0].Score)
+		}
+		assertUser(t, results[0].User, newUser("1", "john.smith@example.com", "John Smith", baseTime))
+
+		none, err := repo.Search(ctx, "zzzz", 10)
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(none) != 0 {
+			t.Errorf("Search() for an unrelated query = %v, want none", searchIDs(none))
+		}
+	})
+
+	t.Run("SearchRankingAndLimit", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("1", "will.smithers@example.com", "Will Smithers", baseTime))
+		mustCreate(t, repo, newUser("2", "ann.smith@example.com", "Ann Smith", baseTime))
+
+		results, err := repo.Search(ctx, "ann smith", 10)
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(results) == 0 || results[0].User.ID != "2" {
+			t.Fatalf("Search() = %v, want Ann Smith first", searchIDs(results))
+		}
+		for i := 1; i < len(results); i++ {
+			if results[i].Score > results[i-1].Score {
+				t.Errorf("Search() scores %v and %v are not in descending order", results[i-1].Score, results[i].Score)
+			}
+		}
+
+		limited, err := repo.Search(ctx, "smith", 1)
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(limited) != 1 {
+			t.Errorf("Search() with limit 1 returned %d results", len(limited))
+		}
+	})
+
+	t.Run("UpdatePartial", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		original := newUser("1", "alice@example.com", "Alice", baseTime)
+		mustCreate(t, repo, original)
+
+		name := "Alice Smith"
+		got, err := repo.Update(ctx, original.ID, &domain.UpdateUserInput{Name: &name})
+		if err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if got.Name != name {
+			t.Errorf("Update() name = %q, want %q", got.Name, name)
+		}
+		if got.Email != original.Email {
+			t.Errorf("Update() email = %q, want unchanged %q", got.Email, original.Email)
+		}
+		if !got.UpdatedAt.After(original.UpdatedAt) {
+			t.Errorf("Update() updated_at = %v, want after %v", got.UpdatedAt, original.UpdatedAt)
+		}
+
+		stored, err := repo.GetByID(ctx, original.ID)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if stored.Name != name || stored.Email != original.Email {
+			t.Errorf("GetByID() after Update() = %+v", stored)
+		}
+	})
+
+	t.Run("UpdateIncrementsVersion", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("1", "alice@example.com", "Alice", baseTime))
+
+		created, err := repo.GetByID(ctx, "1")
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if created.Version != 1 {
+			t.Fatalf("version after Create() = %d, want 1", created.Version)
+		}
+
+		name := "Alice Smith"
+		updated, err := repo.Update(ctx, "1", &domain.UpdateUserInput{Name: &name})
+		if err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if updated.Version != 2 {
+			t.Errorf("version after Update() = %d, want 2", updated.Version)
+		}
+	})
+
+	t.Run("UpdateExpectedVersion", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("1", "alice@example.com", "Alice", baseTime))
+
+		name := "Alice Smith"
+		current := int64(1)
+		if _, err := repo.Update(ctx, "1", &domain.UpdateUserInput{Name: &name, ExpectedVersion: &current}); err != nil {
+			t.Fatalf("Update() with current version error = %v", err)
+		}
+
+		// The same expected version is now stale
+		other := "Alice Jones"
+		_, err := repo.Update(ctx, "1", &domain.UpdateUserInput{Name: &other, ExpectedVersion: &current})
+		if !errors.Is(err, domain.ErrConflict) {
+			t.Fatalf("Update() with stale version error = %v, want %v", err, domain.ErrConflict)
+		}
+
+		stored, err := repo.GetByID(ctx, "1")
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if stored.Name != name || stored.Version != 2 {
+			t.Errorf("GetByID() after conflict = %+v, want name %q at version 2", stored, name)
+		}
+	})
+
+	t.Run("UpdateEmailTaken", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("1", "alice@example.com", "Alice", baseTime))
+		mustCreate(t, repo, newUser("2", "bob@example.com", "Bob", baseTime))
+
+		email := "alice@example.com"
+		_, err := repo.Update(ctx, "2", &domain.UpdateUserInput{Email: &email})
+		if !errors.Is(err, domain.ErrUserAlreadyExists) {
+			t.Fatalf("Update() to a taken email error = %v, want %v", err, domain.ErrUserAlreadyExists)
+		}
+	})
+
+	t.Run("UpdateMissing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		name := "Nobody"
+		_, err := repo.Update(context.Background(), "missing", &domain.UpdateUserInput{Name: &name})
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("Update() error = %v, want %v", err, domain.ErrUserNotFound)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		u := newUser("1", "alice@example.com", "Alice", baseTime)
+		mustCreate(t, repo, u)
+
+		if err := repo.Delete(ctx, u.ID); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if _, err := repo.GetByID(ctx, u.ID); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("GetByID() after Delete() error = %v, want %v", err, domain.ErrUserNotFound)
+		}
+		if _, err := repo.GetByEmail(ctx, u.Email); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("GetByEmail() after Delete() error = %v, want %v", err, domain.ErrUserNotFound)
+		}
+
+		deleted, err := repo.GetByIDIncludingDeleted(ctx, u.ID)
+		if err != nil {
+			t.Fatalf("GetByIDIncludingDeleted() error = %v", err)
+		}
+		if !deleted.IsDeleted() {
+			t.Error("GetByIDIncludingDeleted() deleted_at = nil, want set")
+		}
+		if deleted.Version != 2 {
+			t.Errorf("version after Delete() = %d, want 2", deleted.Version)
+		}
+
+		name := "Alice Smith"
+		if _, err := repo.Update(ctx, u.ID, &domain.UpdateUserInput{Name: &name}); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("Update() after Delete() error = %v, want %v", err, domain.ErrUserNotFound)
+		}
+
+		// The email stays reserved until the user is purged
+		if err := repo.Create(ctx, newUser("2", u.Email, "Alice Again", baseTime)); err == nil {
+			t.Fatal("Create() with a soft-deleted user's email succeeded, want error")
+		}
+	})
+
+	t.Run("DeleteTwice", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("1", "alice@example.com", "Alice", baseTime))
+
+		if err := repo.Delete(ctx, "1"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if err := repo.Delete(ctx, "1"); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("second Delete() error = %v, want %v", err, domain.ErrUserNotFound)
+		}
+	})
+
+	t.Run("DeleteMissing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		err := repo.Delete(context.Background(), "missing")
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("Delete() error = %v, want %v", err, domain.ErrUserNotFound)
+		}
+	})
+
+	t.Run("ListIncludeDeleted", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("1", "alice@example.com", "Alice", baseTime))
+		mustCreate(t, repo, newUser("2", "bob@example.com", "Bob", baseTime.Add(time.Minute)))
+		if err := repo.Delete(ctx, "2"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+
+		active, err := repo.List(ctx, domain.UserFilter{}, domain.DefaultUserSort, 10, 0)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		assertIDs(t, active, "1")
+
+		all, err := repo.List(ctx, domain.UserFilter{IncludeDeleted: true}, domain.DefaultUserSort, 10, 0)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		assertIDs(t, all, "2", "1")
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		u := newUser("1", "alice@example.com", "Alice", baseTime)
+		mustCreate(t, repo, u)
+		if err := repo.Delete(ctx, u.ID); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+
+		restored, err := repo.Restore(ctx, u.ID)
+		if err != nil {
+			t.Fatalf("Restore() error = %v", err)
+		}
+		if restored.IsDeleted() {
+			t.Error("Restore() deleted_at is set, want nil")
+		}
+		if restored.Version != 3 {
+			t.Errorf("version after Restore() = %d, want 3", restored.Version)
+		}
+
+		got, err := repo.GetByID(ctx, u.ID)
+		if err != nil {
+			t.Fatalf("GetByID() after Restore() error = %v", err)
+		}
+		assertUser(t, got, u)
+	})
+
+	t.Run("RestoreNotDeleted", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("1", "alice@example.com", "Alice", baseTime))
+
+		if _, err := repo.Restore(ctx, "1"); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("Restore() of an active user error = %v, want %v", err, domain.ErrUserNotFound)
+		}
+		if _, err := repo.Restore(ctx, "missing"); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("Restore() of a missing user error = %v, want %v", err, domain.ErrUserNotFound)
+		}
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("1", "alice@example.com", "Alice", baseTime))
+		mustCreate(t, repo, newUser("2", "bob@example.com", "Bob", baseTime))
+		if err := repo.Delete(ctx, "1"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+
+		// Both soft-deleted and active users can be purged
+		for _, id := range []string{"1", "2"} {
+			if err := repo.Purge(ctx, id); err != nil {
+				t.Fatalf("Purge(%s) error = %v", id, err)
+			}
+			if _, err := repo.GetByIDIncludingDeleted(ctx, id); !errors.Is(err, domain.ErrUserNotFound) {
+				t.Fatalf("GetByIDIncludingDeleted(%s) after Purge() error = %v, want %v", id, err, domain.ErrUserNotFound)
+			}
+		}
+
+		// The email is free to be used again
+		mustCreate(t, repo, newUser("3", "alice@example.com", "Alice Again", baseTime))
+	})
+
+	t.Run("PurgeMissing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		err := repo.Purge(context.Background(), "missing")
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("Purge() error = %v, want %v", err, domain.ErrUserNotFound)
+		}
+	})
+}
+
+// baseTime is truncated to the microsecond precision of PostgreSQL timestamps
+var baseTime = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newUser(id, email, name string, createdAt time.Time) *domain.User {
+	return &domain.User{
+		ID:        id,
+		Email:     email,
+		Name:      name,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+func mustCreate(t *testing.T, repo ports.UserRepository, user *domain.User) {
+	t.Helper()
+	if err := repo.Create(context.Background(), user); err != nil {
+		t.Fatalf("Create(%s) error = %v", user.ID, err)
+	}
+}
+
+func assertUser(t *testing.T, got, want *domain.User) {
+	t.Helper()
+	if got == nil {
+		t.Fatal("got nil user")
+	}
+	if got.ID != want.ID || got.Email != want.Email || got.Name != want.Name {
+		t.Errorf("got user %+v, want %+v", got, want)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("created_at = %v, want %v", got.CreatedAt, want.CreatedAt)
+	}
+	if !got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Errorf("updated_at = %v, want %v", got.UpdatedAt, want.UpdatedAt)
+	}
+}
+
+func searchIDs(results []*domain.UserSearchResult) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.User.ID
+	}
+	return ids
+}
+
+func assertIDs(t *testing.T, users []*domain.User, want ...string) {
+	t.Helper()
+	got := make([]string, len(users))
+	for i, u := range users {
+		got[i] = u.ID
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got IDs %v, want %v", got, want)
+	}
+}
@@ -6,12 +6,42 @@ import (
 	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
 )
 
-// UserRepository defines the interface for user storage operations
+// UserRepository defines the interface for user storage operations. Reads
+// skip soft-deleted users unless stated otherwise.
 type UserRepository interface {
+	// Create stores a new user, failing with domain.ErrUserAlreadyExists if
+	// its ID or email is taken
 	Create(ctx context.Context, user *domain.User) error
+	// BulkCreate creates users in one batch, returning the status of each in
+	// order. Users whose email is taken, including by an earlier user in the
+	// batch, are skipped and reported as duplicates instead of failing the
+	// batch.
+	BulkCreate(ctx context.Context, users []*domain.User) ([]domain.BulkCreateStatus, error)
 	GetByID(ctx context.Context, id string) (*domain.User, error)
+	GetByIDIncludingDeleted(ctx context.Context, id string) (*domain.User, error)
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
-	List(ctx context.Context, limit, offset int) ([]*domain.User, error)
+	// TakenEmails returns those of emails that belong to a user, including
+	// soft-deleted users, who keep their email until purged
+	TakenEmails(ctx context.Context, emails []string) ([]string, error)
+	List(ctx context.Context, filter domain.UserFilter, sort domain.UserSort, limit, offset int) ([]*domain.User, error)
+	// ListAfter returns up to limit users that come after the cursor in sort
+	// order, starting from the first user if after is nil. The cursor must
+	// have been made for the same sort.
+	ListAfter(ctx context.Context, filter domain.UserFilter, sort domain.UserSort, after *domain.UserCursor, limit int) ([]*domain.User, error)
+	// Count returns the number of users matching filter
+	Count(ctx context.Context, filter domain.UserFilter) (int64, error)
+	// EstimateCount approximates Count without scanning every matching
+	// user where the store allows it, and is exact otherwise
+	EstimateCount(ctx context.Context, filter domain.UserFilter) (int64, error)
+	// Search returns up to limit users matching query, most relevant first
+	Search(ctx context.Context, query string, limit int) ([]*domain.UserSearchResult, error)
+	// Update fails with domain.ErrUserAlreadyExists if the email is changed
+	// to a taken one
 	Update(ctx context.Context, id string, input *domain.UpdateUserInput) (*domain.User, error)
+	// Delete soft-deletes a user
 	Delete(ctx context.Context, id string) error
+	// Restore undoes a soft delete
+	Restore(ctx context.Context, id string) (*domain.User, error)
+	// Purge permanently removes a user, deleted or not
+	Purge(ctx context.Context, id string) error
 }
@@ -9,8 +9,33 @@ import (
 // UserService defines the business logic interface
 type UserService interface {
 	CreateUser(ctx context.Context, input *domain.CreateUserInput) (*domain.User, error)
+	// BulkCreateUsers creates up to domain.MaxBulkCreateUsers users at once,
+	// reporting the outcome of each input in order
+	BulkCreateUsers(ctx context.Context, inputs []*domain.CreateUserInput) ([]*domain.BulkCreateResult, error)
+	// ImportUsers creates up to domain.MaxImportUsers users atomically, in
+	// batches of domain.MaxBulkCreateUsers, reporting the outcome of each
+	// input in order
+	ImportUsers(ctx context.Context, inputs []*domain.CreateUserInput) ([]*domain.BulkCreateResult, error)
 	GetUser(ctx context.Context, id string) (*domain.User, error)
-	ListUsers(ctx context.Context, limit, offset int) ([]*domain.User, error)
+	GetUserIncludingDeleted(ctx context.Context, id string) (*domain.User, error)
+	// TakenEmails returns those of emails that cannot be used for a new
+	// user, because a user has them, deleted or not
+	TakenEmails(ctx context.Context, emails []string) ([]string, error)
+	ListUsers(ctx context.Context, filter domain.UserFilter, sort domain.UserSort, limit, offset int) ([]*domain.User, error)
+	// ListUsersPage returns the page of users following the after cursor,
+	// or the first page if after is empty
+	ListUsersPage(ctx context.Context, filter domain.UserFilter, sort domain.UserSort, after string, limit int) (*domain.UserPage, error)
+	// CountUsers returns the number of users matching filter. With estimate
+	// set it may return an approximation that avoids a full scan.
+	CountUsers(ctx context.Context, filter domain.UserFilter, estimate bool) (int64, error)
+	// SearchUsers finds users by name or email, tolerating typos and
+	// missing letters
+	SearchUsers(ctx context.Context, query string, limit int) ([]*domain.UserSearchResult, error)
 	UpdateUser(ctx context.Context, id string, input *domain.UpdateUserInput) (*domain.User, error)
 	DeleteUser(ctx context.Context, id string) error
+	RestoreUser(ctx context.Context, id string) (*domain.User, error)
+	PurgeUser(ctx context.Context, id string) error
+	// WatchUsers streams the changes made to users after the event with ID
+	// after, or from now on if after is empty
+	WatchUsers(ctx context.Context, after string) (UserEventStream, error)
 }
@@ -0,0 +1,11 @@
+package ports
+
+import "context"
+
+// UnitOfWork runs a group of repository calls atomically
+type UnitOfWork interface {
+	// WithinTx calls fn with a context carrying a transaction. Repository calls
+	// made with that context commit together when fn returns nil and roll back
+	// when it returns an error. Nested calls join the outer transaction.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
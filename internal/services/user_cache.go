@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+	"golang.org/x/sync/singleflight"
+)
+
+// userLoadTimeout bounds a repository read shared by concurrent cache misses
+const userLoadTimeout = 10 * time.Second
+
+// userCacheKeyVersion is bumped whenever the cached representation of
+// domain.User changes so stale entries written by older builds are ignored.
+const userCacheKeyVersion = "v2"
+
+// userCacheKey returns the cache key for a user ID
+func userCacheKey(id string) string {
+	return fmt.Sprintf("user:%s:%s", userCacheKeyVersion, id)
+}
+
+// getCachedUser looks up a user in the cache. A hit on a negative entry
+// returns domain.ErrUserNotFound. Cache failures are logged and treated as
+// misses so that an unavailable cache never fails a read.
+func (s *UserService) getCachedUser(ctx context.Context, id string) (*domain.User, bool, error) {
+	if s.cache == nil {
+		return nil, false, nil
+	}
+
+	key := userCacheKey(id)
+	user, err := s.cache.Get(ctx, key)
+	if errors.Is(err, ports.ErrCacheMiss) {
+		return nil, false, nil
+	}
+	if err != nil {
+		s.cacheFailed(ctx, "get", key, err)
+		return nil, false, nil
+	}
+	// Negative entries are stored as a JSON null
+	if user == nil {
+		return nil, true, domain.ErrUserNotFound
+	}
+	return user, true, nil
+}
+
+// loadUser fetches a user from the repository and populates the cache.
+// Concurrent misses for the same ID share a single repository call, which
+// runs detached from the caller that started it so that its cancellation
+// does not fail the others. Each caller still stops waiting when its own
+// context is done.
+func (s *UserService) loadUser(ctx context.Context, id string) (*domain.User, error) {
+	results := s.group.DoChan(id, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), userLoadTimeout)
+		defer cancel()
+
+		generation := s.cacheGuard.generation(id)
+		user, err := s.repo.GetByID(ctx, id)
+		if err == nil && user == nil {
+			err = domain.ErrUserNotFound
+		}
+		if errors.Is(err, domain.ErrUserNotFound) {
+			s.fillCache(ctx, id, nil, generation)
+			return nil, err
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		s.fillCache(ctx, id, user, generation)
+		return user, nil
+	})
+
+	var result singleflight.Result
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result = <-results:
+	}
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	// Hand every caller its own copy of the shared result
+	user := *result.Val.(*domain.User)
+	return &user, nil
+}
+
+// fillCache caches the result of a repository read made at generation of
+// the user with ID id; a nil user is cached as a negative entry. The result
+// is dropped if the user changed since, as it may be stale.
+func (s *UserService) fillCache(ctx context.Context, id string, user *domain.User, generation uint64) {
+	if s.cache == nil || (user == nil && s.negativeCacheTTL <= 0) {
+		return
+	}
+
+	stripe := s.cacheGuard.lock(id)
+	defer stripe.mu.Unlock()
+	if stripe.generation != generation {
+		return
+	}
+	if user == nil {
+		s.setCachedUser(ctx, id, nil, s.negativeCacheTTL)
+	} else {
+		s.setCachedUser(ctx, id, user, s.cacheTTL)
+	}
+}
+
+// cacheUser stores a user just written to the repository in the cache.
+// Errors are only logged as the database remains the source of truth.
+func (s *UserService) cacheUser(ctx context.Context, user *domain.User) {
+	if s.cache == nil || user == nil {
+		return
+	}
+
+	stripe := s.changed(user.ID)
+	defer stripe.mu.Unlock()
+	s.setCachedUser(ctx, user.ID, user, s.cacheTTL)
+}
+
+// invalidateUser removes a user from the cache
+func (s *UserService) invalidateUser(ctx context.Context, id string) {
+	if s.cache == nil {
+		return
+	}
+
+	stripe := s.changed(id)
+	defer stripe.mu.Unlock()
+	key := userCacheKey(id)
+	if err := s.cache.Delete(ctx, key); err != nil {
+		s.cacheFailed(ctx, "delete", key, err)
+	}
+}
+
+// changed records a change to the user with ID id, so that loads already
+// under way neither cache their result nor are joined by new callers. It
+// returns the user's stripe locked, for the caller to update the cache
+// before unlocking it.
+func (s *UserService) changed(id string) *cacheStripe {
+	stripe := s.cacheGuard.lock(id)
+	stripe.generation++
+	s.group.Forget(id)
+	return stripe
+}
+
+// setCachedUser stores user, or a negative entry if it is nil, for ttl
+// spread by jitter
+func (s *UserService) setCachedUser(ctx context.Context, id string, user *domain.User, ttl time.Duration) {
+	key := userCacheKey(id)
+	if err := s.cache.Set(ctx, key, user, s.jitter(ttl)); err != nil {
+		s.cacheFailed(ctx, "set", key, err)
+	}
+}
+
+// cacheFailed logs a failed cache operation. It logs at debug level because
+// an unavailable cache fails every request until it recovers.
+func (s *UserService) cacheFailed(ctx context.Context, op, key string, err error) {
+	s.log.DebugContext(ctx, "user cache operation failed", "op", op, "key", key, "error", err)
+}
+
+// cacheStripes is the number of locks guarding user cache writes
+const cacheStripes = 64
+
+// cacheStripe orders the cache writes for the users hashed to it
+type cacheStripe struct {
+	mu sync.Mutex
+	// generation counts the changes made to those users
+	generation uint64
+}
+
+// userCacheGuard keeps loads from caching users that changed while they
+// were read. Users share a fixed number of stripes, so a change may also
+// drop the load of an unrelated user, which is then merely not cached.
+type userCacheGuard struct {
+	stripes [cacheStripes]cacheStripe
+}
+
+// lock locks and returns the stripe of the user with ID id
+func (g *userCacheGuard) lock(id string) *cacheStripe {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	stripe := &g.stripes[h.Sum32()%cacheStripes]
+	stripe.mu.Lock()
+	return stripe
+}
+
+// generation returns the current generation of the user with ID id
+func (g *userCacheGuard) generation(id string) uint64 {
+	stripe := g.lock(id)
+	defer stripe.mu.Unlock()
+	return stripe.generation
+}
+
+// jitter spreads a TTL by up to cacheJitter of its length so that entries
+// written together do not all expire at the same moment.
+func (s *UserService) jitter(ttl time.Duration) time.Duration {
+	if s.cacheJitter <= 0 || ttl <= 0 {
+		return ttl
+	}
+	spread := time.Duration(float64(ttl) * s.cacheJitter)
+	if spread <= 0 {
+		return ttl
+	}
+	return ttl + rand.N(spread)
+}
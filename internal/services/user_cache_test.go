@@ -0,0 +1,370 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/memory"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+// countingRepository counts the GetByID calls reaching a repository
+type countingRepository struct {
+	ports.UserRepository
+	gets atomic.Int64
+}
+
+func (r *countingRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	r.gets.Add(1)
+	return r.UserRepository.GetByID(ctx, id)
+}
+
+// failingCache is a cache whose every operation fails
+type failingCache struct{}
+
+var errCacheDown = errors.New("cache down")
+
+func (failingCache) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	return errCacheDown
+}
+
+func (failingCache) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, errCacheDown
+}
+
+func (failingCache) Delete(ctx context.Context, key string) error {
+	return errCacheDown
+}
+
+func (failingCache) Exists(ctx context.Context, key string) (bool, error) {
+	return false, errCacheDown
+}
+
+func (failingCache) GetMany(ctx context.Context, keys []string) (map[string][]byte, error) {
+	return nil, errCacheDown
+}
+
+func (failingCache) SetMany(ctx context.Context, values map[string][]byte, expiration time.Duration) error {
+	return errCacheDown
+}
+
+// newCachedService returns a service over a counting in-memory repository
+// holding one user, and the cache it reads through
+func newCachedService(t *testing.T, opts ...Option) (ports.UserService, *countingRepository, ports.CacheRepository, *domain.User) {
+	t.Helper()
+	repo := &countingRepository{UserRepository: memory.NewUserRepository()}
+	user := &domain.User{
+		ID:        "u1",
+		Email:     "ann@example.com",
+		Name:      "Ann",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Version:   1,
+	}
+	if err := repo.Create(context.Background(), user); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	cacheRepo := memory.NewCacheRepository(100)
+	return NewUserService(repo, cacheRepo, opts...), repo, cacheRepo, user
+}
+
+func TestGetUserReadsThroughCache(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, cacheRepo, user := newCachedService(t)
+
+	for i := 0; i < 3; i++ {
+		got, err := svc.GetUser(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("GetUser() error = %v", err)
+		}
+		if got.Email != user.Email {
+			t.Errorf("GetUser() email = %q, want %q", got.Email, user.Email)
+		}
+	}
+	if gets := repo.gets.Load(); gets != 1 {
+		t.Errorf("repository GetByID calls = %d, want 1", gets)
+	}
+	if ok, _ := cacheRepo.Exists(ctx, userCacheKey(user.ID)); !ok {
+		t.Errorf("user is not cached after GetUser()")
+	}
+}
+
+func TestUpdateUserReplacesCachedUser(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, user := newCachedService(t)
+	if _, err := svc.GetUser(ctx, user.ID); err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+
+	name := "Annie"
+	if _, err := svc.UpdateUser(ctx, user.ID, &domain.UpdateUserInput{Name: &name}); err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	got, err := svc.GetUser(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+	if got.Name != name {
+		t.Errorf("GetUser() after UpdateUser() name = %q, want %q", got.Name, name)
+	}
+}
+
+func TestFailedUpdateInvalidatesCachedUser(t *testing.T) {
+	ctx := context.Background()
+	svc, _, cacheRepo, user := newCachedService(t)
+	if _, err := svc.GetUser(ctx, user.ID); err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+
+	stale := int64(7)
+	name := "Annie"
+	_, err := svc.UpdateUser(ctx, user.ID, &domain.UpdateUserInput{Name: &name, ExpectedVersion: &stale})
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("UpdateUser() with a stale version error = %v, want %v", err, domain.ErrConflict)
+	}
+	if ok, _ := cacheRepo.Exists(ctx, userCacheKey(user.ID)); ok {
+		t.Errorf("user is still cached after a failed UpdateUser()")
+	}
+}
+
+func TestDeleteUserInvalidatesCachedUser(t *testing.T) {
+	ctx := context.Background()
+	svc, _, cacheRepo, user := newCachedService(t)
+	if _, err := svc.GetUser(ctx, user.ID); err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+
+	if err := svc.DeleteUser(ctx, user.ID); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+	if ok, _ := cacheRepo.Exists(ctx, userCacheKey(user.ID)); ok {
+		t.Errorf("user is still cached after DeleteUser()")
+	}
+	if _, err := svc.GetUser(ctx, user.ID); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("GetUser() after DeleteUser() error = %v, want %v", err, domain.ErrUserNotFound)
+	}
+}
+
+func TestCacheFailuresAreLoggedAsMisses(t *testing.T) {
+	var logs bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	repo := &countingRepository{UserRepository: memory.NewUserRepository()}
+	user := &domain.User{ID: "u1", Email: "ann@example.com", Name: "Ann", Version: 1}
+	if err := repo.Create(context.Background(), user); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	svc := NewUserService(repo, failingCache{}, WithLogger(log))
+
+	got, err := svc.GetUser(context.Background(), user.ID)
+	if err != nil {
+		t.Fatalf("GetUser() with a failing cache error = %v", err)
+	}
+	if got.ID != user.ID {
+		t.Errorf("GetUser() ID = %q, want %q", got.ID, user.ID)
+	}
+	if out := logs.String(); !strings.Contains(out, "op=get") || !strings.Contains(out, errCacheDown.Error()) {
+		t.Errorf("log output = %q, want the failed get and its error", out)
+	}
+}
+
+// blockingRepository holds its first GetByID call, after reading the user,
+// until release is closed
+type blockingRepository struct {
+	ports.UserRepository
+	gets    atomic.Int64
+	entered chan struct{}
+	release chan struct{}
+}
+
+func newBlockingRepository(repo ports.UserRepository) *blockingRepository {
+	return &blockingRepository{UserRepository: repo, entered: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (r *blockingRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	user, err := r.UserRepository.GetByID(ctx, id)
+	if r.gets.Add(1) == 1 {
+		close(r.entered)
+		<-r.release
+	}
+	return user, err
+}
+
+// nilRepository reports missing users as a nil user without an error
+type nilRepository struct {
+	ports.UserRepository
+}
+
+func (nilRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	return nil, nil
+}
+
+func newRepositoryWithUser(t *testing.T) (ports.UserRepository, *domain.User) {
+	t.Helper()
+	repo := memory.NewUserRepository()
+	user := &domain.User{ID: "u1", Email: "ann@example.com", Name: "Ann", Version: 1}
+	if err := repo.Create(context.Background(), user); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return repo, user
+}
+
+func TestGetUserCoalescesMisses(t *testing.T) {
+	base, user := newRepositoryWithUser(t)
+	repo := newBlockingRepository(base)
+	svc := NewUserService(repo, memory.NewCacheRepository(100))
+
+	const callers = 5
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			_, err := svc.GetUser(context.Background(), user.ID)
+			errs <- err
+		}()
+	}
+	<-repo.entered
+	// Let the other callers join the load under way
+	time.Sleep(50 * time.Millisecond)
+	close(repo.release)
+
+	for i := 0; i < callers; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("GetUser() error = %v", err)
+		}
+	}
+	if gets := repo.gets.Load(); gets != 1 {
+		t.Errorf("repository GetByID calls = %d, want 1", gets)
+	}
+}
+
+func TestGetUserSurvivesCancelledLeader(t *testing.T) {
+	base, user := newRepositoryWithUser(t)
+	repo := newBlockingRepository(base)
+	svc := NewUserService(repo, memory.NewCacheRepository(100))
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := svc.GetUser(leaderCtx, user.ID)
+		leaderErr <- err
+	}()
+	<-repo.entered
+
+	followerErr := make(chan error, 1)
+	go func() {
+		_, err := svc.GetUser(context.Background(), user.ID)
+		followerErr <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("GetUser() with a cancelled context error = %v, want %v", err, context.Canceled)
+	}
+
+	close(repo.release)
+	if err := <-followerErr; err != nil {
+		t.Errorf("GetUser() joined to a cancelled caller error = %v, want nil", err)
+	}
+}
+
+func TestGetUserDoesNotCacheStaleLoad(t *testing.T) {
+	ctx := context.Background()
+	base, user := newRepositoryWithUser(t)
+	repo := newBlockingRepository(base)
+	cacheRepo := memory.NewCacheRepository(100)
+	svc := NewUserService(repo, cacheRepo)
+
+	loaded := make(chan error, 1)
+	go func() {
+		_, err := svc.GetUser(ctx, user.ID)
+		loaded <- err
+	}()
+	<-repo.entered
+
+	// The load has read the old name; change it before the load caches it
+	name := "Annie"
+	if _, err := svc.UpdateUser(ctx, user.ID, &domain.UpdateUserInput{Name: &name}); err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	close(repo.release)
+	if err := <-loaded; err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+
+	got, err := svc.GetUser(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+	if got.Name != name {
+		t.Errorf("GetUser() after a load raced UpdateUser() name = %q, want %q", got.Name, name)
+	}
+}
+
+func TestGetUserCachesNotFound(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		ttl      time.Duration
+		wantGets int64
+	}{
+		{"Enabled", 30 * time.Second, 1},
+		{"Disabled", 0, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &countingRepository{UserRepository: memory.NewUserRepository()}
+			svc := NewUserService(repo, memory.NewCacheRepository(100), WithNegativeCacheTTL(tt.ttl))
+
+			for i := 0; i < 2; i++ {
+				if _, err := svc.GetUser(ctx, "missing"); !errors.Is(err, domain.ErrUserNotFound) {
+					t.Errorf("GetUser() of a missing user error = %v, want %v", err, domain.ErrUserNotFound)
+				}
+			}
+			if gets := repo.gets.Load(); gets != tt.wantGets {
+				t.Errorf("repository GetByID calls = %d, want %d", gets, tt.wantGets)
+			}
+		})
+	}
+}
+
+func TestGetUserTreatsNilUserAsNotFound(t *testing.T) {
+	svc := NewUserService(nilRepository{}, memory.NewCacheRepository(100))
+	if _, err := svc.GetUser(context.Background(), "u1"); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("GetUser() with a nil user error = %v, want %v", err, domain.ErrUserNotFound)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	const ttl = time.Minute
+	tests := []struct {
+		name   string
+		jitter float64
+		max    time.Duration
+	}{
+		{"Disabled", 0, ttl},
+		{"Tenth", 0.1, ttl + ttl/10},
+		{"Full", 1, 2 * ttl},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &UserService{cacheJitter: tt.jitter}
+			for i := 0; i < 1000; i++ {
+				got := s.jitter(ttl)
+				if got < ttl || got > tt.max || (tt.jitter > 0 && got == tt.max) {
+					t.Fatalf("jitter(%v) = %v, want within [%v, %v)", ttl, got, ttl, tt.max)
+				}
+			}
+		})
+	}
+
+	s := &UserService{cacheJitter: 0.5}
+	if got := s.jitter(0); got != 0 {
+		t.Errorf("jitter(0) = %v, want 0", got)
+	}
+}
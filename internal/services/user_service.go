@@ -2,103 +2,494 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/cache"
 	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
 	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
 	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultUserCacheTTL is how long a user stays cached when no TTL is configured
+	DefaultUserCacheTTL = 5 * time.Minute
+	// DefaultUserNegativeCacheTTL is how long a missing user ID is remembered
+	DefaultUserNegativeCacheTTL = 30 * time.Second
+	// DefaultCacheJitter is the fraction by which cache TTLs are randomly extended
+	DefaultCacheJitter = 0.1
+	// MaxSearchResults caps the number of results a search returns
+	MaxSearchResults = 100
 )
 
 // UserService implements the UserService interface
 type UserService struct {
-	repo  ports.UserRepository
-	cache ports.CacheRepository
+	repo             ports.UserRepository
+	uow              ports.UnitOfWork
+	cache            *cache.TypedCache[*domain.User]
+	cacheTTL         time.Duration
+	negativeCacheTTL time.Duration
+	cacheJitter      float64
+	group            singleflight.Group
+	cacheGuard       userCacheGuard
+	events           ports.UserEventBus
+	log              *slog.Logger
 }
 
-// NewUserService creates a new user service
-func NewUserService(repo ports.UserRepository, cache ports.CacheRepository) ports.UserService {
-	return &UserService{
-		repo:  repo,
-		cache: cache,
+// Option configures a UserService
+type Option func(*UserService)
+
+// WithCacheTTL sets how long users are kept in the cache
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(s *UserService) {
+		if ttl > 0 {
+			s.cacheTTL = ttl
+		}
 	}
 }
 
-// CreateUser creates a new user
-func (s *UserService) CreateUser(ctx context.Context, input *domain.CreateUserInput) (*domain.User, error) {
-	// Validate input
-	if input.Email == "" || input.Name == "" {
-		return nil, domain.ErrInvalidInput
+// WithNegativeCacheTTL sets how long a lookup for a missing user is cached.
+// A zero TTL disables negative caching.
+func WithNegativeCacheTTL(ttl time.Duration) Option {
+	return func(s *UserService) {
+		if ttl >= 0 {
+			s.negativeCacheTTL = ttl
+		}
 	}
+}
 
-	// Check if user already exists
-	existingUser, err := s.repo.GetByEmail(ctx, input.Email)
-	if err == nil && existingUser != nil {
-		return nil, domain.ErrUserAlreadyExists
+// WithCacheJitter sets the fraction (0 to 1) by which cache TTLs are randomly
+// extended. A zero jitter disables it.
+func WithCacheJitter(jitter float64) Option {
+	return func(s *UserService) {
+		if jitter >= 0 && jitter <= 1 {
+			s.cacheJitter = jitter
+		}
+	}
+}
+
+// WithUnitOfWork makes multi-step operations atomic using uow, which must
+// share its transactions with the service's repository
+func WithUnitOfWork(uow ports.UnitOfWork) Option {
+	return func(s *UserService) {
+		if uow != nil {
+			s.uow = uow
+		}
+	}
+}
+
+// WithEventBus publishes an event to bus after every change to a user, which
+// WatchUsers needs
+func WithEventBus(bus ports.UserEventBus) Option {
+	return func(s *UserService) {
+		if bus != nil {
+			s.events = bus
+		}
+	}
+}
+
+// WithLogger logs cache failures to log at debug level and failures to
+// publish events at error level. Without it they are not logged.
+func WithLogger(log *slog.Logger) Option {
+	return func(s *UserService) {
+		if log != nil {
+			s.log = log
+		}
+	}
+}
+
+// NewUserService creates a new user service
+func NewUserService(repo ports.UserRepository, cacheRepo ports.CacheRepository, opts ...Option) ports.UserService {
+	s := &UserService{
+		repo:             repo,
+		uow:              noopUnitOfWork{},
+		cacheTTL:         DefaultUserCacheTTL,
+		negativeCacheTTL: DefaultUserNegativeCacheTTL,
+		cacheJitter:      DefaultCacheJitter,
+		log:              slog.New(slog.DiscardHandler),
+	}
+	if cacheRepo != nil {
+		s.cache = cache.NewTypedCache[*domain.User](cacheRepo, cache.JSONCodec[*domain.User]{})
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CreateUser creates a new user. The input is normalized, lowercasing the
+// email, and validated first.
+func (s *UserService) CreateUser(ctx context.Context, input *domain.CreateUserInput) (*domain.User, error) {
+	normalized := input.Normalize()
+	input = &normalized
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	// Create user
 	user := &domain.User{
 		ID:        uuid.New().String(),
 		Email:     input.Email,
 		Name:      input.Name,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+		Version:   1,
 	}
 
-	if err := s.repo.Create(ctx, user); err != nil {
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		// Check if user already exists
+		_, err := s.repo.GetByEmail(ctx, input.Email)
+		if err == nil {
+			return domain.ErrUserAlreadyExists
+		}
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			return err
+		}
+
+		// Create user
+		return s.repo.Create(ctx, user)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.cacheUser(ctx, user)
+	s.publish(ctx, newUserEvent(domain.UserCreated, user.ID, user))
+
 	return user, nil
 }
 
-// GetUser retrieves a user by ID
-func (s *UserService) GetUser(ctx context.Context, id string) (*domain.User, error) {
-	user, err := s.repo.GetByID(ctx, id)
+// BulkCreateUsers creates many users in one repository call. Invalid inputs
+// and taken emails are reported per user rather than failing the batch.
+// Created users are not cached.
+func (s *UserService) BulkCreateUsers(ctx context.Context, inputs []*domain.CreateUserInput) ([]*domain.BulkCreateResult, error) {
+	if err := domain.ValidateBulkSize(len(inputs), domain.MaxBulkCreateUsers); err != nil {
+		return nil, err
+	}
+
+	results, events, err := s.bulkCreate(ctx, inputs)
 	if err != nil {
 		return nil, err
 	}
-	if user == nil {
-		return nil, domain.ErrUserNotFound
+	s.publish(ctx, events...)
+
+	return results, nil
+}
+
+// ImportUsers creates up to domain.MaxImportUsers users in batches of
+// domain.MaxBulkCreateUsers, all in one unit of work: if a batch fails, the
+// users of earlier batches are not created either. Outcomes are reported as
+// by BulkCreateUsers.
+func (s *UserService) ImportUsers(ctx context.Context, inputs []*domain.CreateUserInput) ([]*domain.BulkCreateResult, error) {
+	if err := domain.ValidateBulkSize(len(inputs), domain.MaxImportUsers); err != nil {
+		return nil, err
 	}
-	return user, nil
+
+	results := make([]*domain.BulkCreateResult, 0, len(inputs))
+	var events []*domain.UserEvent
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		for start := 0; start < len(inputs); start += domain.MaxBulkCreateUsers {
+			batch := inputs[start:min(start+domain.MaxBulkCreateUsers, len(inputs))]
+			created, createdEvents, err := s.bulkCreate(ctx, batch)
+			if err != nil {
+				return err
+			}
+			for _, result := range created {
+				result.Index += start
+			}
+			results = append(results, created...)
+			events = append(events, createdEvents...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publish(ctx, events...)
+
+	return results, nil
+}
+
+// bulkCreate creates a batch of users in one repository call, returning the
+// outcome of each input and the events to publish once they are committed
+func (s *UserService) bulkCreate(ctx context.Context, inputs []*domain.CreateUserInput) ([]*domain.BulkCreateResult, []*domain.UserEvent, error) {
+	results := make([]*domain.BulkCreateResult, len(inputs))
+	users := make([]*domain.User, 0, len(inputs))
+	valid := make([]*domain.BulkCreateResult, 0, len(inputs))
+	now := time.Now()
+	for i, input := range inputs {
+		results[i] = &domain.BulkCreateResult{Index: i}
+		normalized := input.Normalize()
+		input = &normalized
+		if err := input.Validate(); err != nil {
+			results[i].Status = domain.BulkCreateInvalid
+			results[i].Error = err.Error()
+			continue
+		}
+
+		results[i].User = &domain.User{
+			ID:        uuid.New().String(),
+			Email:     input.Email,
+			Name:      input.Name,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Version:   1,
+		}
+		users = append(users, results[i].User)
+		valid = append(valid, results[i])
+	}
+	if len(users) == 0 {
+		return results, nil, nil
+	}
+
+	statuses, err := s.repo.BulkCreate(ctx, users)
+	if err != nil {
+		return nil, nil, err
+	}
+	events := make([]*domain.UserEvent, 0, len(statuses))
+	for i, status := range statuses {
+		valid[i].Status = status
+		if status != domain.BulkCreateCreated {
+			valid[i].User = nil
+			valid[i].Error = domain.ErrUserAlreadyExists.Error()
+			continue
+		}
+		events = append(events, newUserEvent(domain.UserCreated, valid[i].User.ID, valid[i].User))
+	}
+
+	return results, events, nil
+}
+
+// GetUser retrieves a user by ID, reading through the cache
+func (s *UserService) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	if user, ok, err := s.getCachedUser(ctx, id); ok {
+		return user, err
+	}
+
+	return s.loadUser(ctx, id)
 }
 
-// ListUsers retrieves a list of users
-func (s *UserService) ListUsers(ctx context.Context, limit, offset int) ([]*domain.User, error) {
-	return s.repo.List(ctx, limit, offset)
+// GetUserIncludingDeleted retrieves a user by ID even if it was soft-deleted.
+// Deleted users are never cached, so it always reads the repository.
+func (s *UserService) GetUserIncludingDeleted(ctx context.Context, id string) (*domain.User, error) {
+	return s.repo.GetByIDIncludingDeleted(ctx, id)
 }
 
-// UpdateUser updates a user
-func (s *UserService) UpdateUser(ctx context.Context, id string, input *domain.UpdateUserInput) (*domain.User, error) {
-	// Check if user exists
-	user, err := s.repo.GetByID(ctx, id)
+// TakenEmails returns those of emails that belong to a user, deleted or not.
+// The emails are compared as given, so they should be normalized first.
+func (s *UserService) TakenEmails(ctx context.Context, emails []string) ([]string, error) {
+	return s.repo.TakenEmails(ctx, emails)
+}
+
+// ListUsers retrieves a list of users. A zero sort lists the newest users
+// first.
+func (s *UserService) ListUsers(ctx context.Context, filter domain.UserFilter, sort domain.UserSort, limit, offset int) ([]*domain.User, error) {
+	if err := domain.ValidateOffsetPage(limit, offset); err != nil {
+		return nil, err
+	}
+	sort, err := validateListing(filter, sort)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.List(ctx, filter, sort, limit, offset)
+}
+
+// ListUsersPage retrieves a page of users using keyset pagination, which
+// stays fast on deep pages and is stable while users are being created. A
+// cursor is only valid with the sort it was issued for.
+func (s *UserService) ListUsersPage(ctx context.Context, filter domain.UserFilter, sort domain.UserSort, after string, limit int) (*domain.UserPage, error) {
+	if limit <= 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	sort, err := validateListing(filter, sort)
 	if err != nil {
 		return nil, err
 	}
-	if user == nil {
-		return nil, domain.ErrUserNotFound
+
+	var cursor *domain.UserCursor
+	if after != "" {
+		c, err := domain.DecodeUserCursor(after)
+		if err != nil {
+			return nil, err
+		}
+		if c.Sort != sort {
+			return nil, domain.ErrInvalidCursor
+		}
+		cursor = &c
 	}
 
-	// Update user
-	updatedUser, err := s.repo.Update(ctx, id, input)
+	// Fetch one extra user to learn whether another page follows
+	users, err := s.repo.ListAfter(ctx, filter, sort, cursor, limit+1)
 	if err != nil {
 		return nil, err
 	}
 
+	page := &domain.UserPage{Users: users, Sort: sort}
+	if len(users) > limit {
+		page.Users = users[:limit]
+		page.NextCursor = domain.NewUserCursor(users[limit-1], sort).Encode()
+	}
+
+	return page, nil
+}
+
+// CountUsers returns the number of users matching filter, or the
+// repository's estimate of it when estimate is set
+func (s *UserService) CountUsers(ctx context.Context, filter domain.UserFilter, estimate bool) (int64, error) {
+	if err := filter.Validate(); err != nil {
+		return 0, err
+	}
+
+	if estimate {
+		return s.repo.EstimateCount(ctx, filter)
+	}
+	return s.repo.Count(ctx, filter)
+}
+
+// SearchUsers finds users whose name or email matches query, best match
+// first. Deleted users are never returned.
+func (s *UserService) SearchUsers(ctx context.Context, query string, limit int) ([]*domain.UserSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || limit <= 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	if limit > MaxSearchResults {
+		limit = MaxSearchResults
+	}
+
+	return s.repo.Search(ctx, query, limit)
+}
+
+// validateListing checks a listing's filter and sort, returning the sort to
+// use
+func validateListing(filter domain.UserFilter, sort domain.UserSort) (domain.UserSort, error) {
+	if err := filter.Validate(); err != nil {
+		return sort, err
+	}
+	if sort == (domain.UserSort{}) {
+		return domain.DefaultUserSort, nil
+	}
+	return sort, sort.Validate()
+}
+
+// UpdateUser updates a user, normalizing and validating the input first
+func (s *UserService) UpdateUser(ctx context.Context, id string, input *domain.UpdateUserInput) (*domain.User, error) {
+	normalized := input.Normalize()
+	input = &normalized
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	var updatedUser *domain.User
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		// Check if user exists
+		user, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return domain.ErrUserNotFound
+		}
+
+		// Update user
+		updatedUser, err = s.repo.Update(ctx, id, input)
+		return err
+	})
+	if err != nil {
+		s.invalidateUser(ctx, id)
+		return nil, err
+	}
+
+	s.cacheUser(ctx, updatedUser)
+	s.publish(ctx, newUserEvent(domain.UserUpdated, id, updatedUser))
+
 	return updatedUser, nil
 }
 
-// DeleteUser deletes a user
+// DeleteUser soft-deletes a user
 func (s *UserService) DeleteUser(ctx context.Context, id string) error {
-	// Check if user exists
-	user, err := s.repo.GetByID(ctx, id)
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		// Check if user exists
+		user, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return domain.ErrUserNotFound
+		}
+
+		return s.repo.Delete(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateUser(ctx, id)
+	s.publish(ctx, newUserEvent(domain.UserDeleted, id, nil))
+
+	return nil
+}
+
+// RestoreUser undoes a soft delete
+func (s *UserService) RestoreUser(ctx context.Context, id string) (*domain.User, error) {
+	user, err := s.repo.Restore(ctx, id)
 	if err != nil {
+		return nil, err
+	}
+
+	s.cacheUser(ctx, user)
+	s.publish(ctx, newUserEvent(domain.UserRestored, id, user))
+
+	return user, nil
+}
+
+// PurgeUser permanently removes a user, whether or not it was soft-deleted
+func (s *UserService) PurgeUser(ctx context.Context, id string) error {
+	if err := s.repo.Purge(ctx, id); err != nil {
 		return err
 	}
-	if user == nil {
-		return domain.ErrUserNotFound
+
+	s.invalidateUser(ctx, id)
+	s.publish(ctx, newUserEvent(domain.UserPurged, id, nil))
+
+	return nil
+}
+
+// WatchUsers streams the changes made to users after the event with ID after,
+// or from now on if after is empty
+func (s *UserService) WatchUsers(ctx context.Context, after string) (ports.UserEventStream, error) {
+	if s.events == nil {
+		return nil, fmt.Errorf("%w: no event bus is configured", domain.ErrInternalServer)
+	}
+	return s.events.Subscribe(ctx, after)
+}
+
+// newUserEvent creates an event reporting a change to the user with ID id
+func newUserEvent(eventType domain.UserEventType, id string, user *domain.User) *domain.UserEvent {
+	return &domain.UserEvent{Type: eventType, UserID: id, User: user, Time: time.Now()}
+}
+
+// publish reports committed changes to watchers. Like cache failures, a
+// failure to publish does not fail the change, which has already been made;
+// it is logged and watchers miss the events instead. Publishing outlives a
+// cancelled request for the same reason.
+func (s *UserService) publish(ctx context.Context, events ...*domain.UserEvent) {
+	if s.events == nil || len(events) == 0 {
+		return
+	}
+	if err := s.events.Publish(context.WithoutCancel(ctx), events...); err != nil {
+		s.log.ErrorContext(ctx, "publishing user events failed", "type", events[0].Type, "user", events[0].UserID, "count", len(events), "error", err)
 	}
+}
+
+// noopUnitOfWork runs operations without a transaction. It is used when no
+// unit of work is configured.
+type noopUnitOfWork struct{}
 
-	return s.repo.Delete(ctx, id)
+func (noopUnitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
 }
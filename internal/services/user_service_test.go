@@ -0,0 +1,155 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/adapters/memory"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/domain"
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/internal/ports"
+)
+
+// failingBulkRepository fails every BulkCreate call after the first
+type failingBulkRepository struct {
+	ports.UserRepository
+	calls int
+}
+
+var errBulkCreate = errors.New("bulk create failed")
+
+func (r *failingBulkRepository) BulkCreate(ctx context.Context, users []*domain.User) ([]domain.BulkCreateStatus, error) {
+	r.calls++
+	if r.calls > 1 {
+		return nil, errBulkCreate
+	}
+	return r.UserRepository.BulkCreate(ctx, users)
+}
+
+// newImportService returns a service over repo, which wraps base, with a
+// unit of work spanning base
+func newImportService(t *testing.T, base, repo ports.UserRepository) ports.UserService {
+	t.Helper()
+	uow, err := memory.NewUnitOfWork(base)
+	if err != nil {
+		t.Fatalf("NewUnitOfWork() error = %v", err)
+	}
+	return NewUserService(repo, nil, WithUnitOfWork(uow))
+}
+
+// importInputs returns n inputs with distinct emails
+func importInputs(n int) []*domain.CreateUserInput {
+	inputs := make([]*domain.CreateUserInput, n)
+	for i := range inputs {
+		inputs[i] = &domain.CreateUserInput{Email: fmt.Sprintf("user%d@example.com", i), Name: fmt.Sprintf("User %d", i)}
+	}
+	return inputs
+}
+
+func TestImportUsersReportsEveryInput(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository()
+	service := newImportService(t, repo, repo)
+
+	// The last input falls in a second batch and repeats the first email
+	inputs := append(importInputs(domain.MaxBulkCreateUsers), &domain.CreateUserInput{Email: "user0@example.com", Name: "Again"})
+	inputs[1].Email = "invalid"
+	results, err := service.ImportUsers(ctx, inputs)
+	if err != nil {
+		t.Fatalf("ImportUsers() error = %v", err)
+	}
+	if len(results) != len(inputs) {
+		t.Fatalf("ImportUsers() returned %d results, want %d", len(results), len(inputs))
+	}
+	for i, result := range results {
+		want := domain.BulkCreateCreated
+		switch i {
+		case 1:
+			want = domain.BulkCreateInvalid
+		case len(inputs) - 1:
+			want = domain.BulkCreateDuplicate
+		}
+		if result.Index != i || result.Status != want {
+			t.Errorf("result %d = index %d, %s, want index %d, %s", i, result.Index, result.Status, i, want)
+		}
+	}
+
+	if count, err := repo.Count(ctx, domain.UserFilter{}); err != nil || count != int64(len(inputs)-2) {
+		t.Errorf("Count() = %d, %v, want %d", count, err, len(inputs)-2)
+	}
+}
+
+func TestImportUsersIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	base := memory.NewUserRepository()
+	service := newImportService(t, base, &failingBulkRepository{UserRepository: base})
+
+	// The second batch fails after the first was written
+	if _, err := service.ImportUsers(ctx, importInputs(domain.MaxBulkCreateUsers+1)); !errors.Is(err, errBulkCreate) {
+		t.Fatalf("ImportUsers() error = %v, want %v", err, errBulkCreate)
+	}
+	if count, err := base.Count(ctx, domain.UserFilter{}); err != nil || count != 0 {
+		t.Errorf("Count() after a failed import = %d, %v, want 0", count, err)
+	}
+}
+
+func TestListUsersRejectsNegativePaging(t *testing.T) {
+	service := NewUserService(memory.NewUserRepository(), nil)
+	tests := []struct {
+		limit, offset int
+		wantField     string
+	}{
+		{limit: -1, offset: 0, wantField: "limit"},
+		{limit: 10, offset: -1, wantField: "offset"},
+	}
+	for _, tt := range tests {
+		_, err := service.ListUsers(context.Background(), domain.UserFilter{}, domain.UserSort{}, tt.limit, tt.offset)
+		var validationErr *domain.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Violations[0].Field != tt.wantField {
+			t.Errorf("ListUsers(limit %d, offset %d) error = %v, want a violation of %s", tt.limit, tt.offset, err, tt.wantField)
+		}
+	}
+}
+
+func TestImportUsersRejectsTooManyUsers(t *testing.T) {
+	repo := memory.NewUserRepository()
+	service := newImportService(t, repo, repo)
+
+	_, err := service.ImportUsers(context.Background(), importInputs(domain.MaxImportUsers+1))
+	var validationErr *domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("ImportUsers() of %d users error = %v, want a validation error", domain.MaxImportUsers+1, err)
+	}
+	if count, _ := repo.Count(context.Background(), domain.UserFilter{}); count != 0 {
+		t.Errorf("ImportUsers() created %d users, want none", count)
+	}
+}
+
+// failingEventBus fails every Publish call
+type failingEventBus struct {
+	ports.UserEventBus
+}
+
+var errPublish = errors.New("event bus down")
+
+func (failingEventBus) Publish(ctx context.Context, events ...*domain.UserEvent) error {
+	return errPublish
+}
+
+func TestPublishFailuresAreLogged(t *testing.T) {
+	var logs bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&logs, nil))
+	service := NewUserService(memory.NewUserRepository(), nil, WithEventBus(failingEventBus{}), WithLogger(log))
+
+	user, err := service.CreateUser(context.Background(), &domain.CreateUserInput{Email: "ann@example.com", Name: "Ann"})
+	if err != nil {
+		t.Fatalf("CreateUser() with a failing event bus error = %v", err)
+	}
+	if out := logs.String(); !strings.Contains(out, user.ID) || !strings.Contains(out, errPublish.Error()) {
+		t.Errorf("log output = %q, want the unpublished event and its error", out)
+	}
+}
@@ -0,0 +1,2 @@
+-- Add a version column for optimistic concurrency control
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
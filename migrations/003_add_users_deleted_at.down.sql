@@ -0,0 +1,3 @@
+-- Drop soft delete support
+DROP INDEX IF EXISTS idx_users_active_created_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,5 @@
+-- Index the (created_at, id) sort key so that cursor pagination can seek
+-- straight to the next page
+DROP INDEX IF EXISTS idx_users_active_created_at;
+CREATE INDEX IF NOT EXISTS idx_users_active_created_at_id ON users(created_at DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_created_at_id ON users(created_at DESC, id DESC);
@@ -0,0 +1,3 @@
+-- Drop listing indexes
+DROP INDEX IF EXISTS idx_users_lower_email_pattern;
+DROP INDEX IF EXISTS idx_users_name_id;
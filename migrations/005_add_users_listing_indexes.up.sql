@@ -0,0 +1,3 @@
+-- Support sorting users by name and filtering them by email prefix
+CREATE INDEX IF NOT EXISTS idx_users_name_id ON users(name, id);
+CREATE INDEX IF NOT EXISTS idx_users_lower_email_pattern ON users(lower(email) text_pattern_ops);
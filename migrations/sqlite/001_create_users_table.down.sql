@@ -0,0 +1,3 @@
+-- Drop users table
+DROP INDEX IF EXISTS idx_users_email;
+DROP TABLE IF EXISTS users;
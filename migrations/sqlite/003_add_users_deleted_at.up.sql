@@ -0,0 +1,5 @@
+-- Add soft delete support; emails stay reserved until a user is purged
+ALTER TABLE users ADD COLUMN deleted_at DATETIME;
+
+-- Speed up listing of users that have not been deleted
+CREATE INDEX IF NOT EXISTS idx_users_active_created_at ON users(created_at DESC) WHERE deleted_at IS NULL;
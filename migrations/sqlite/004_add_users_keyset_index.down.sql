@@ -0,0 +1,4 @@
+-- Restore the created_at-only listing index
+DROP INDEX IF EXISTS idx_users_created_at_id;
+DROP INDEX IF EXISTS idx_users_active_created_at_id;
+CREATE INDEX IF NOT EXISTS idx_users_active_created_at ON users(created_at DESC) WHERE deleted_at IS NULL;
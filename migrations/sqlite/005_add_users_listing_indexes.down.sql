@@ -0,0 +1,2 @@
+-- Drop listing indexes
+DROP INDEX IF EXISTS idx_users_name_id;
@@ -0,0 +1,2 @@
+-- Support sorting users by name
+CREATE INDEX IF NOT EXISTS idx_users_name_id ON users(name, id);
@@ -2,31 +2,62 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds all application configuration
 type Config struct {
 	Server   ServerConfig
+	GRPC     GRPCConfig
 	Database DatabaseConfig
 	Redis    RedisConfig
+	Cache    CacheConfig
+	Events   EventsConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
 	HTTPPort string
 	GRPCPort string
+	// ShutdownTimeout bounds how long in-flight requests may drain on shutdown
+	ShutdownTimeout time.Duration
+}
+
+// GRPCConfig holds configuration for the gRPC interceptors
+type GRPCConfig struct {
+	// AccessLog logs every RPC with its status code and latency
+	AccessLog bool
+	// LogFormat is the access log format, text or json
+	LogFormat string
+	// LogLevel is the lowest level of structured log records written; debug
+	// also logs cache failures
+	LogLevel slog.Level
+	// RequestIDHeader is the metadata key that carries request IDs
+	RequestIDHeader string
+	// Reflection registers the server reflection service; disable it in
+	// production to hide the API surface
+	Reflection bool
+	// HealthCheckInterval is how often the health service pings dependencies
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout bounds each dependency ping
+	HealthCheckTimeout time.Duration
 }
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
+	Driver   string
 	Host     string
 	Port     int
 	User     string
 	Password string
 	Database string
 	SSLMode  string
+	// SQLitePath is the database file used when Driver is sqlite
+	SQLitePath string
 }
 
 // RedisConfig holds Redis configuration
@@ -37,6 +68,29 @@ type RedisConfig struct {
 	DB       int
 }
 
+// CacheConfig holds caching configuration
+type CacheConfig struct {
+	Driver              string
+	MemoryCapacity      int
+	LocalTTL            time.Duration
+	InvalidationChannel string
+	UserTTL             time.Duration
+	UserNegativeTTL     time.Duration
+	TTLJitter           float64
+}
+
+// EventsConfig holds configuration for user change events
+type EventsConfig struct {
+	// Driver selects the event bus: redis, which reaches every instance, or
+	// memory, which only reaches watchers of the same instance
+	Driver string
+	// Stream is the Redis stream holding the events
+	Stream string
+	// History is the approximate number of events retained for watchers
+	// resuming after a disconnect
+	History int
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	dbPort, err := strconv.Atoi(getEnv("DB_PORT", "5432"))
@@ -44,6 +98,11 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid DB_PORT: %w", err)
 	}
 
+	dbDriver := getEnv("DB_DRIVER", "postgres")
+	if dbDriver != "postgres" && dbDriver != "sqlite" && dbDriver != "memory" {
+		return nil, fmt.Errorf("invalid DB_DRIVER: %q (expected postgres, sqlite or memory)", dbDriver)
+	}
+
 	redisPort, err := strconv.Atoi(getEnv("REDIS_PORT", "6379"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid REDIS_PORT: %w", err)
@@ -54,18 +113,128 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid REDIS_DB: %w", err)
 	}
 
+	shutdownTimeout, err := time.ParseDuration(getEnv("SHUTDOWN_TIMEOUT", "15s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SHUTDOWN_TIMEOUT: %w", err)
+	}
+	if shutdownTimeout <= 0 {
+		return nil, fmt.Errorf("invalid SHUTDOWN_TIMEOUT: must be positive")
+	}
+
+	grpcAccessLog, err := strconv.ParseBool(getEnv("GRPC_ACCESS_LOG", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid GRPC_ACCESS_LOG: %w", err)
+	}
+
+	grpcLogFormat := getEnv("GRPC_LOG_FORMAT", "text")
+	if grpcLogFormat != "text" && grpcLogFormat != "json" {
+		return nil, fmt.Errorf("invalid GRPC_LOG_FORMAT: %q (expected text or json)", grpcLogFormat)
+	}
+
+	var grpcLogLevel slog.Level
+	if err := grpcLogLevel.UnmarshalText([]byte(getEnv("GRPC_LOG_LEVEL", "info"))); err != nil {
+		return nil, fmt.Errorf("invalid GRPC_LOG_LEVEL: %w", err)
+	}
+
+	grpcReflection, err := strconv.ParseBool(getEnv("GRPC_REFLECTION", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid GRPC_REFLECTION: %w", err)
+	}
+
+	grpcHealthCheckInterval, err := time.ParseDuration(getEnv("GRPC_HEALTH_CHECK_INTERVAL", "10s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid GRPC_HEALTH_CHECK_INTERVAL: %w", err)
+	}
+	if grpcHealthCheckInterval <= 0 {
+		return nil, fmt.Errorf("invalid GRPC_HEALTH_CHECK_INTERVAL: must be positive")
+	}
+
+	grpcHealthCheckTimeout, err := time.ParseDuration(getEnv("GRPC_HEALTH_CHECK_TIMEOUT", "2s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid GRPC_HEALTH_CHECK_TIMEOUT: %w", err)
+	}
+	if grpcHealthCheckTimeout <= 0 {
+		return nil, fmt.Errorf("invalid GRPC_HEALTH_CHECK_TIMEOUT: must be positive")
+	}
+
+	cacheDriver := getEnv("CACHE_DRIVER", "redis")
+	if cacheDriver != "redis" && cacheDriver != "memory" && cacheDriver != "tiered" {
+		return nil, fmt.Errorf("invalid CACHE_DRIVER: %q (expected redis, memory or tiered)", cacheDriver)
+	}
+
+	cacheMemoryCapacity, err := strconv.Atoi(getEnv("CACHE_MEMORY_CAPACITY", "10000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_MEMORY_CAPACITY: %w", err)
+	}
+	if cacheMemoryCapacity <= 0 {
+		return nil, fmt.Errorf("invalid CACHE_MEMORY_CAPACITY: must be positive")
+	}
+
+	cacheLocalTTL, err := time.ParseDuration(getEnv("CACHE_LOCAL_TTL", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_LOCAL_TTL: %w", err)
+	}
+
+	cacheUserTTL, err := time.ParseDuration(getEnv("CACHE_USER_TTL", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_USER_TTL: %w", err)
+	}
+
+	cacheUserNegativeTTL, err := time.ParseDuration(getEnv("CACHE_USER_NEGATIVE_TTL", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_USER_NEGATIVE_TTL: %w", err)
+	}
+
+	cacheTTLJitter, err := strconv.ParseFloat(getEnv("CACHE_TTL_JITTER", "0.1"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_TTL_JITTER: %w", err)
+	}
+	if cacheTTLJitter < 0 || cacheTTLJitter > 1 {
+		return nil, fmt.Errorf("invalid CACHE_TTL_JITTER: must be between 0 and 1")
+	}
+
+	// Events follow the cache onto Redis unless configured otherwise
+	eventsDriver := "redis"
+	if cacheDriver == "memory" {
+		eventsDriver = "memory"
+	}
+	eventsDriver = getEnv("EVENTS_DRIVER", eventsDriver)
+	if eventsDriver != "redis" && eventsDriver != "memory" {
+		return nil, fmt.Errorf("invalid EVENTS_DRIVER: %q (expected redis or memory)", eventsDriver)
+	}
+
+	eventsHistory, err := strconv.Atoi(getEnv("EVENTS_HISTORY", "10000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EVENTS_HISTORY: %w", err)
+	}
+	if eventsHistory <= 0 {
+		return nil, fmt.Errorf("invalid EVENTS_HISTORY: must be positive")
+	}
+
 	return &Config{
 		Server: ServerConfig{
-			HTTPPort: getEnv("HTTP_PORT", "8080"),
-			GRPCPort: getEnv("GRPC_PORT", "9090"),
+			HTTPPort:        getEnv("HTTP_PORT", "8080"),
+			GRPCPort:        getEnv("GRPC_PORT", "9090"),
+			ShutdownTimeout: shutdownTimeout,
+		},
+		GRPC: GRPCConfig{
+			AccessLog:           grpcAccessLog,
+			LogFormat:           grpcLogFormat,
+			LogLevel:            grpcLogLevel,
+			RequestIDHeader:     strings.ToLower(getEnv("GRPC_REQUEST_ID_HEADER", "x-request-id")),
+			Reflection:          grpcReflection,
+			HealthCheckInterval: grpcHealthCheckInterval,
+			HealthCheckTimeout:  grpcHealthCheckTimeout,
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     dbPort,
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "postgres"),
-			Database: getEnv("DB_NAME", "hexagonal_app"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Driver:     dbDriver,
+			Host:       getEnv("DB_HOST", "localhost"),
+			Port:       dbPort,
+			User:       getEnv("DB_USER", "postgres"),
+			Password:   getEnv("DB_PASSWORD", "postgres"),
+			Database:   getEnv("DB_NAME", "hexagonal_app"),
+			SSLMode:    getEnv("DB_SSLMODE", "disable"),
+			SQLitePath: getEnv("SQLITE_PATH", "hexagonal_app.db"),
 		},
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
@@ -73,6 +242,20 @@ func Load() (*Config, error) {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       redisDB,
 		},
+		Cache: CacheConfig{
+			Driver:              cacheDriver,
+			MemoryCapacity:      cacheMemoryCapacity,
+			LocalTTL:            cacheLocalTTL,
+			InvalidationChannel: getEnv("CACHE_INVALIDATION_CHANNEL", "cache:invalidate"),
+			UserTTL:             cacheUserTTL,
+			UserNegativeTTL:     cacheUserNegativeTTL,
+			TTLJitter:           cacheTTLJitter,
+		},
+		Events: EventsConfig{
+			Driver:  eventsDriver,
+			Stream:  getEnv("EVENTS_STREAM", "users:events"),
+			History: eventsHistory,
+		},
 	}, nil
 }
 
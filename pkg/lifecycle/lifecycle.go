@@ -0,0 +1,213 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/logger"
+	"google.golang.org/grpc"
+)
+
+// Server is a long-running component started and stopped by a Manager
+type Server interface {
+	// Serve blocks until the server stops. It returns nil once the server
+	// was shut down.
+	Serve() error
+	// Shutdown stops the server, waiting for in-flight requests until ctx
+	// is done
+	Shutdown(ctx context.Context) error
+}
+
+type namedServer struct {
+	name   string
+	server Server
+}
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+// Manager runs servers until a shutdown is requested or one of them fails,
+// then stops them and tears down their dependencies. Shutdown happens in
+// three steps: the shutdown hooks run, the servers drain within the shutdown
+// timeout, and the closers run in reverse order of registration.
+type Manager struct {
+	log     *logger.Logger
+	timeout time.Duration
+
+	servers []namedServer
+	hooks   []func()
+	closers []closer
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// New creates a manager that gives servers at most timeout to drain
+func New(timeout time.Duration, log *logger.Logger) *Manager {
+	return &Manager{log: log, timeout: timeout}
+}
+
+// AddServer registers a server started by Run
+func (m *Manager) AddServer(name string, server Server) {
+	m.servers = append(m.servers, namedServer{name: name, server: server})
+}
+
+// OnShutdown registers a function run when shutdown begins, before the
+// servers stop accepting requests
+func (m *Manager) OnShutdown(hook func()) {
+	m.hooks = append(m.hooks, hook)
+}
+
+// AddCloser registers a dependency closed after the servers have stopped.
+// Dependencies are closed in reverse order of registration, so one can be
+// registered after the dependencies it uses.
+func (m *Manager) AddCloser(name string, close func() error) {
+	m.closers = append(m.closers, closer{name: name, close: close})
+}
+
+// Run starts every server and blocks until ctx is done or a server fails,
+// then shuts everything down. It returns an error if a server failed, did not
+// drain in time or a dependency failed to close.
+func (m *Manager) Run(ctx context.Context) error {
+	serveErrs := make(chan error, len(m.servers))
+	for _, s := range m.servers {
+		go func() {
+			if err := s.server.Serve(); err != nil {
+				serveErrs <- fmt.Errorf("%s: %w", s.name, err)
+				return
+			}
+			serveErrs <- nil
+		}()
+	}
+
+	var errs []error
+	select {
+	case <-ctx.Done():
+		m.log.Info("Shutting down...")
+	case err := <-serveErrs:
+		if err == nil {
+			err = errors.New("a server stopped unexpectedly")
+		}
+		m.log.Errorf("Server failed, shutting down: %v", err)
+		errs = append(errs, err)
+	}
+
+	errs = append(errs, m.shutdown())
+	errs = append(errs, m.Close())
+	return errors.Join(errs...)
+}
+
+// shutdown runs the shutdown hooks, then stops the servers concurrently
+func (m *Manager) shutdown() error {
+	for _, hook := range m.hooks {
+		hook()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	errs := make([]error, len(m.servers))
+	var wg sync.WaitGroup
+	for i, s := range m.servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.server.Shutdown(ctx); err != nil {
+				m.log.Errorf("%s did not shut down gracefully: %v", s.name, err)
+				errs[i] = fmt.Errorf("shutting down %s: %w", s.name, err)
+				return
+			}
+			m.log.Infof("%s stopped", s.name)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Close closes the registered dependencies in reverse order of registration.
+// Run calls it after stopping the servers; calling it again does nothing, so
+// it can also be deferred to clean up when startup fails before Run.
+func (m *Manager) Close() error {
+	m.closeOnce.Do(func() {
+		var errs []error
+		for i := len(m.closers) - 1; i >= 0; i-- {
+			c := m.closers[i]
+			if err := c.close(); err != nil {
+				m.log.Errorf("Failed to close %s: %v", c.name, err)
+				errs = append(errs, fmt.Errorf("closing %s: %w", c.name, err))
+				continue
+			}
+			m.log.Infof("%s closed", c.name)
+		}
+		m.closeErr = errors.Join(errs...)
+	})
+	return m.closeErr
+}
+
+// grpcServer adapts a gRPC server to Server
+type grpcServer struct {
+	server   *grpc.Server
+	listener net.Listener
+}
+
+// GRPCServer returns a Server serving server on listener. Shutdown drains
+// in-flight RPCs with GracefulStop and cancels the remaining ones when its
+// context is done.
+func GRPCServer(server *grpc.Server, listener net.Listener) Server {
+	return &grpcServer{server: server, listener: listener}
+}
+
+func (s *grpcServer) Serve() error {
+	return s.server.Serve(s.listener)
+}
+
+func (s *grpcServer) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		<-stopped
+		return ctx.Err()
+	}
+}
+
+// httpServer adapts an HTTP server to Server
+type httpServer struct {
+	server   *http.Server
+	listener net.Listener
+}
+
+// HTTPServer returns a Server serving server on listener
+func HTTPServer(server *http.Server, listener net.Listener) Server {
+	return &httpServer{server: server, listener: listener}
+}
+
+func (s *httpServer) Serve() error {
+	if err := s.server.Serve(s.listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *httpServer) Shutdown(ctx context.Context) error {
+	if err := s.server.Shutdown(ctx); err != nil {
+		// Cut the connections that are still open
+		s.server.Close()
+		return err
+	}
+	return nil
+}
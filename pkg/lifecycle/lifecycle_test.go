@@ -0,0 +1,125 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexanderbkl/golang-hexagonal-boilerplate/pkg/logger"
+)
+
+// fakeServer serves until it is shut down or fails with serveErr
+type fakeServer struct {
+	name     string
+	serveErr error
+	drain    time.Duration
+	events   *events
+	stop     chan struct{}
+}
+
+func newFakeServer(name string, ev *events) *fakeServer {
+	return &fakeServer{name: name, events: ev, stop: make(chan struct{})}
+}
+
+func (s *fakeServer) Serve() error {
+	if s.serveErr != nil {
+		return s.serveErr
+	}
+	<-s.stop
+	return nil
+}
+
+func (s *fakeServer) Shutdown(ctx context.Context) error {
+	defer close(s.stop)
+	select {
+	case <-time.After(s.drain):
+		s.events.add("stop " + s.name)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// events records the order of shutdown steps
+type events struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (e *events) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.list = append(e.list, event)
+}
+
+func (e *events) get() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return slices.Clone(e.list)
+}
+
+func newTestManager(timeout time.Duration, ev *events) *Manager {
+	m := New(timeout, logger.New())
+	m.OnShutdown(func() { ev.add("hook") })
+	m.AddCloser("postgres", func() error { ev.add("close postgres"); return nil })
+	m.AddCloser("redis", func() error { ev.add("close redis"); return nil })
+	return m
+}
+
+func TestManagerShutdownOrder(t *testing.T) {
+	ev := &events{}
+	m := newTestManager(time.Second, ev)
+	m.AddServer("grpc", newFakeServer("grpc", ev))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []string{"hook", "stop grpc", "close redis", "close postgres"}
+	if got := ev.get(); !slices.Equal(got, want) {
+		t.Errorf("shutdown steps = %v, want %v", got, want)
+	}
+	if err := m.Close(); err != nil || len(ev.get()) != len(want) {
+		t.Errorf("a second Close() closed dependencies again")
+	}
+}
+
+func TestManagerServerFailure(t *testing.T) {
+	ev := &events{}
+	m := newTestManager(time.Second, ev)
+	failing := newFakeServer("http", ev)
+	failing.serveErr = errors.New("address in use")
+	m.AddServer("grpc", newFakeServer("grpc", ev))
+	m.AddServer("http", failing)
+
+	err := m.Run(context.Background())
+	if err == nil || !errors.Is(err, failing.serveErr) {
+		t.Fatalf("Run() error = %v, want the server failure", err)
+	}
+	if got := ev.get(); !slices.Contains(got, "stop grpc") || !slices.Contains(got, "close postgres") {
+		t.Errorf("shutdown steps = %v, want the other server stopped and dependencies closed", got)
+	}
+}
+
+func TestManagerShutdownTimeout(t *testing.T) {
+	ev := &events{}
+	m := newTestManager(10*time.Millisecond, ev)
+	slow := newFakeServer("grpc", ev)
+	slow.drain = time.Minute
+	m.AddServer("grpc", slow)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := m.Run(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if got := ev.get(); !slices.Contains(got, "close postgres") {
+		t.Errorf("shutdown steps = %v, want dependencies closed after the timeout", got)
+	}
+}
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"log"
+	"log/slog"
 	"os"
 )
 
@@ -48,3 +49,13 @@ func (l *Logger) Fatal(v ...interface{}) {
 func (l *Logger) Fatalf(format string, v ...interface{}) {
 	l.errorLogger.Fatalf(format, v...)
 }
+
+// NewStructured creates a structured logger writing records at level or
+// above to stdout in format, either text or json
+func NewStructured(format string, level slog.Level) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if format == "json" {
+		return slog.New(slog.NewJSONHandler(os.Stdout, opts))
+	}
+	return slog.New(slog.NewTextHandler(os.Stdout, opts))
+}
@@ -0,0 +1,49 @@
+// Package trigram computes trigram similarity the way PostgreSQL's pg_trgm
+// extension does, for adapters that have to rank text without it.
+package trigram
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultThreshold is pg_trgm's default similarity threshold
+const DefaultThreshold = 0.3
+
+// Similarity returns the number of trigrams a and b share divided by the
+// number of distinct trigrams in either, from 0 (nothing in common) to 1
+func Similarity(a, b string) float64 {
+	ta, tb := Trigrams(a), Trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for t := range ta {
+		if _, ok := tb[t]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// Trigrams returns the set of trigrams in s. Like pg_trgm, it lowercases s,
+// splits it into words of letters and digits, and pads each word with two
+// spaces in front and one behind.
+func Trigrams(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, word := range Words(s) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
+
+// Words returns the lowercased runs of letters and digits in s
+func Words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
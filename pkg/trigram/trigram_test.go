@@ -0,0 +1,26 @@
+package trigram
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	// Expected values come from pg_trgm's similarity()
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"word", "two words", 4.0 / 11},
+		{"John Smith", "jon smth", 5.0 / 15},
+		{"cat", "CAT", 1},
+		{"cat", "dog", 0},
+		{"", "dog", 0},
+		{"a-b", "a b", 1},
+	}
+	for _, tt := range tests {
+		if got := Similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}